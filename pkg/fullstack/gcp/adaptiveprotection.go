@@ -0,0 +1,143 @@
+package gcp
+
+import (
+	"fmt"
+	"slices"
+
+	compute "github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/compute"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+const (
+	defaultAdaptiveProtectionRuleVisibility = "STANDARD"
+
+	// Google defaults of the auto-deployed rules
+	defaultAutoDeployLoadThreshold             = 0.8
+	defaultAutoDeployConfidenceThreshold       = 0.5
+	defaultAutoDeployImpactedBaselineThreshold = 0.01
+	defaultAutoDeployExpirationSeconds         = 7200
+	defaultAutoDeployDenyStatusCode            = 403
+
+	// The auto-deployed rules are evaluated after the client IP allowlist and before the
+	// preconfigured WAF rules
+	autoDeployRulePriority = 15
+)
+
+var (
+	adaptiveProtectionRuleVisibilities = []string{"STANDARD", "PREMIUM"}
+	autoDeployDenyStatuses             = []int{403, 404, 502}
+)
+
+func applyAdaptiveProtectionDefaults(args *AdaptiveProtectionArgs) {
+	if args.RuleVisibility == "" {
+		args.RuleVisibility = defaultAdaptiveProtectionRuleVisibility
+	}
+
+	autoDeploy := args.AutoDeploy
+	if autoDeploy == nil {
+		return
+	}
+
+	if autoDeploy.LoadThreshold == 0 {
+		autoDeploy.LoadThreshold = defaultAutoDeployLoadThreshold
+	}
+
+	if autoDeploy.ConfidenceThreshold == 0 {
+		autoDeploy.ConfidenceThreshold = defaultAutoDeployConfidenceThreshold
+	}
+
+	if autoDeploy.ImpactedBaselineThreshold == 0 {
+		autoDeploy.ImpactedBaselineThreshold = defaultAutoDeployImpactedBaselineThreshold
+	}
+
+	if autoDeploy.ExpirationSeconds == 0 {
+		autoDeploy.ExpirationSeconds = defaultAutoDeployExpirationSeconds
+	}
+
+	if autoDeploy.DenyStatusCode == 0 {
+		autoDeploy.DenyStatusCode = defaultAutoDeployDenyStatusCode
+	}
+}
+
+func validateAdaptiveProtection(args *NetworkArgs) error {
+	adaptiveProtection := args.AdaptiveProtection
+
+	if !args.EnableCloudArmor {
+		return fmt.Errorf("adaptive protection requires Cloud Armor to be enabled")
+	}
+
+	if !slices.Contains(adaptiveProtectionRuleVisibilities, adaptiveProtection.RuleVisibility) {
+		return fmt.Errorf("rule visibility must be one of %v, got %q", adaptiveProtectionRuleVisibilities, adaptiveProtection.RuleVisibility)
+	}
+
+	autoDeploy := adaptiveProtection.AutoDeploy
+	if autoDeploy == nil {
+		return nil
+	}
+
+	thresholds := []struct {
+		name  string
+		value float64
+	}{
+		{"load threshold", autoDeploy.LoadThreshold},
+		{"confidence threshold", autoDeploy.ConfidenceThreshold},
+		{"impacted baseline threshold", autoDeploy.ImpactedBaselineThreshold},
+	}
+	for _, threshold := range thresholds {
+		if threshold.value <= 0 || threshold.value > 1 {
+			return fmt.Errorf("auto deploy %s must be greater than 0 and at most 1, got %g", threshold.name, threshold.value)
+		}
+	}
+
+	if autoDeploy.ExpirationSeconds < 0 {
+		return fmt.Errorf("auto deploy expiration must be greater than 0, got %d", autoDeploy.ExpirationSeconds)
+	}
+
+	if !slices.Contains(autoDeployDenyStatuses, autoDeploy.DenyStatusCode) {
+		return fmt.Errorf("auto deploy deny status code must be one of %v, got %d", autoDeployDenyStatuses, autoDeploy.DenyStatusCode)
+	}
+
+	return nil
+}
+
+// newAdaptiveProtectionConfig enables the layer 7 DDoS defense of Adaptive Protection, which
+// detects the attacks and suggests the rules blocking them. With auto deploy, the suggested
+// rules are enforced for the expiration once the thresholds are met.
+//
+// See:
+// https://cloud.google.com/armor/docs/adaptive-protection-overview
+// https://cloud.google.com/armor/docs/adaptive-protection-auto-deploy
+func newAdaptiveProtectionConfig(args *AdaptiveProtectionArgs) *compute.SecurityPolicyAdaptiveProtectionConfigArgs {
+	config := &compute.SecurityPolicyAdaptiveProtectionConfigArgs{
+		Layer7DdosDefenseConfig: &compute.SecurityPolicyAdaptiveProtectionConfigLayer7DdosDefenseConfigArgs{
+			Enable:         pulumi.Bool(true),
+			RuleVisibility: pulumi.String(args.RuleVisibility),
+		},
+	}
+
+	if args.AutoDeploy != nil {
+		config.AutoDeployConfig = &compute.SecurityPolicyAdaptiveProtectionConfigAutoDeployConfigArgs{
+			LoadThreshold:             pulumi.Float64(args.AutoDeploy.LoadThreshold),
+			ConfidenceThreshold:       pulumi.Float64(args.AutoDeploy.ConfidenceThreshold),
+			ImpactedBaselineThreshold: pulumi.Float64(args.AutoDeploy.ImpactedBaselineThreshold),
+			ExpirationSec:             pulumi.Int(args.AutoDeploy.ExpirationSeconds),
+		}
+	}
+
+	return config
+}
+
+// newAutoDeployRule creates the placeholder rule enforcing the rules auto-deployed by Adaptive
+// Protection during an attack.
+func newAutoDeployRule(args *AdaptiveProtectionAutoDeployArgs) *compute.SecurityPolicyRuleTypeArgs {
+	return &compute.SecurityPolicyRuleTypeArgs{
+		Action:      pulumi.String(fmt.Sprintf("deny(%d)", args.DenyStatusCode)),
+		Description: pulumi.String("Adaptive Protection auto-deployed rules"),
+		Priority:    pulumi.Int(autoDeployRulePriority),
+		Match: &compute.SecurityPolicyRuleMatchArgs{
+			Expr: &compute.SecurityPolicyRuleMatchExprArgs{
+				Expression: pulumi.String("evaluateAdaptiveProtectionAutoDeploy()"),
+			},
+		},
+	}
+}
@@ -0,0 +1,183 @@
+package gcp
+
+import (
+	"fmt"
+	"strconv"
+
+	compute "github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/compute"
+	"github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/projects"
+	"github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/recaptcha"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// Env vars for bot management configuration
+//
+//nolint:revive // Environment variable names should match their actual env var names
+const (
+	RECAPTCHA_SITE_KEY = "RECAPTCHA_SITE_KEY"
+)
+
+const (
+	botManagementActionRedirect = "redirect"
+	botManagementActionDeny     = "deny"
+
+	defaultBotManagementScoreThreshold = 0.5
+
+	// Bot management rules are evaluated after the preconfigured WAF rules and before the rate limits
+	botManagementRulesBasePriority = 50
+	maxBotManagementPaths          = 10
+)
+
+func applyBotManagementDefaults(args *NetworkArgs) {
+	botManagement := args.BotManagement
+
+	if botManagement.Action == "" {
+		botManagement.Action = botManagementActionRedirect
+	}
+
+	if botManagement.ScoreThreshold == 0 {
+		botManagement.ScoreThreshold = defaultBotManagementScoreThreshold
+	}
+
+	if len(botManagement.AllowedDomains) == 0 {
+		botManagement.AllowedDomains = certificateDomains(args)
+	}
+}
+
+func validateBotManagement(args *NetworkArgs) error {
+	botManagement := args.BotManagement
+
+	if !args.EnableCloudArmor {
+		return fmt.Errorf("bot management requires Cloud Armor to be enabled")
+	}
+
+	if botManagement.Action != botManagementActionRedirect && botManagement.Action != botManagementActionDeny {
+		return fmt.Errorf("action must be %q or %q, got %q", botManagementActionRedirect, botManagementActionDeny, botManagement.Action)
+	}
+
+	if botManagement.ScoreThreshold <= 0 || botManagement.ScoreThreshold > 1 {
+		return fmt.Errorf("score threshold must be greater than 0 and at most 1, got %g", botManagement.ScoreThreshold)
+	}
+
+	if len(botManagement.Paths) == 0 {
+		return fmt.Errorf("at least one path is required")
+	}
+
+	if len(botManagement.Paths) > maxBotManagementPaths {
+		return fmt.Errorf("at most %d paths are supported, got %d", maxBotManagementPaths, len(botManagement.Paths))
+	}
+
+	for _, path := range botManagement.Paths {
+		if err := validateCELPath(path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newRecaptchaKeys creates the reCAPTCHA Enterprise keys of the Cloud Armor bot management: a
+// score key the frontend uses to attach session tokens to the requests, and an invisible key
+// rendering the challenge page the low scoring clients are redirected to.
+//
+// See:
+// https://cloud.google.com/armor/docs/bot-management
+// https://cloud.google.com/recaptcha/docs/implement-session-token
+func (f *FullStack) newRecaptchaKeys(ctx *pulumi.Context, args *BotManagementArgs) error {
+	recaptchaAPI, err := projects.NewService(ctx, f.NewResourceName("bot-management", "recaptcha-api", 63), &projects.ServiceArgs{
+		Project:                  pulumi.String(f.Project),
+		Service:                  pulumi.String("recaptchaenterprise.googleapis.com"),
+		DisableOnDestroy:         pulumi.Bool(false),
+		DisableDependentServices: pulumi.Bool(false),
+	},
+		pulumi.Parent(f),
+		pulumi.RetainOnDelete(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enable reCAPTCHA Enterprise API: %w", err)
+	}
+
+	labels := mergeLabels(f.Labels, pulumi.StringMap{
+		"bot_management": pulumi.String("true"),
+	})
+
+	sessionKeyName := f.NewResourceName("bot-management", "session-token-key", 63)
+	sessionKey, err := recaptcha.NewEnterpriseKey(ctx, sessionKeyName, &recaptcha.EnterpriseKeyArgs{
+		DisplayName: pulumi.String(sessionKeyName),
+		Project:     pulumi.String(f.Project),
+		Labels:      labels,
+		WebSettings: &recaptcha.EnterpriseKeyWebSettingsArgs{
+			IntegrationType: pulumi.String("SCORE"),
+			AllowedDomains:  toStringArray(args.AllowedDomains),
+		},
+		WafSettings: &recaptcha.EnterpriseKeyWafSettingsArgs{
+			WafService: pulumi.String("CA"),
+			WafFeature: pulumi.String("SESSION_TOKEN"),
+		},
+	}, pulumi.Parent(f), pulumi.DependsOn([]pulumi.Resource{recaptchaAPI}))
+	if err != nil {
+		return fmt.Errorf("failed to create reCAPTCHA session token key: %w", err)
+	}
+	f.recaptchaSessionTokenKey = sessionKey
+
+	challengeKeyName := f.NewResourceName("bot-management", "challenge-page-key", 63)
+	challengeKey, err := recaptcha.NewEnterpriseKey(ctx, challengeKeyName, &recaptcha.EnterpriseKeyArgs{
+		DisplayName: pulumi.String(challengeKeyName),
+		Project:     pulumi.String(f.Project),
+		Labels:      labels,
+		WebSettings: &recaptcha.EnterpriseKeyWebSettingsArgs{
+			IntegrationType: pulumi.String("INVISIBLE"),
+			AllowedDomains:  toStringArray(args.AllowedDomains),
+		},
+		WafSettings: &recaptcha.EnterpriseKeyWafSettingsArgs{
+			WafService: pulumi.String("CA"),
+			WafFeature: pulumi.String("CHALLENGE_PAGE"),
+		},
+	}, pulumi.Parent(f), pulumi.DependsOn([]pulumi.Resource{recaptchaAPI}))
+	if err != nil {
+		return fmt.Errorf("failed to create reCAPTCHA challenge page key: %w", err)
+	}
+	f.recaptchaChallengePageKey = challengeKey
+
+	return nil
+}
+
+// newBotManagementRules creates a rule per path challenging or denying the requests without a
+// valid session token, or scoring below the threshold. Clients that passed the challenge page
+// carry an exemption cookie and are let through.
+func newBotManagementRules(args *BotManagementArgs) compute.SecurityPolicyRuleTypeArray {
+	threshold := strconv.FormatFloat(args.ScoreThreshold, 'f', -1, 64)
+
+	var botManagementRules compute.SecurityPolicyRuleTypeArray
+	for index, path := range args.Paths {
+		lowScore := fmt.Sprintf("(!token.recaptcha_session.valid || token.recaptcha_session.score < %s)", threshold)
+
+		rule := &compute.SecurityPolicyRuleTypeArgs{
+			Description: pulumi.String(fmt.Sprintf("bot management rule for %s", path)),
+			Priority:    pulumi.Int(botManagementRulesBasePriority + index),
+		}
+
+		if args.Action == botManagementActionRedirect {
+			rule.Action = pulumi.String("redirect")
+			rule.RedirectOptions = &compute.SecurityPolicyRuleRedirectOptionsArgs{
+				Type: pulumi.String("GOOGLE_RECAPTCHA"),
+			}
+			rule.Match = &compute.SecurityPolicyRuleMatchArgs{
+				Expr: &compute.SecurityPolicyRuleMatchExprArgs{
+					Expression: pulumi.String(fmt.Sprintf("%s && !token.recaptcha_exemption.valid && %s", pathPrefixExpression(path), lowScore)),
+				},
+			}
+		} else {
+			rule.Action = pulumi.String("deny(403)")
+			rule.Match = &compute.SecurityPolicyRuleMatchArgs{
+				Expr: &compute.SecurityPolicyRuleMatchExprArgs{
+					Expression: pulumi.String(fmt.Sprintf("%s && %s", pathPrefixExpression(path), lowScore)),
+				},
+			}
+		}
+
+		botManagementRules = append(botManagementRules, rule)
+	}
+
+	return botManagementRules
+}
@@ -45,14 +45,23 @@ func (f *FullStack) deployCache(ctx *pulumi.Context, args *CacheInstanceArgs) er
 		return fmt.Errorf("failed to create Redis instance: %w", err)
 	}
 
+	// With a Shared VPC, the connector attaches to a subnet of the host project
+	var subnetGrants []pulumi.Resource
+	if args.NetworkProject != "" {
+		subnetGrants, err = f.grantConnectorSubnetUser(ctx, args)
+		if err != nil {
+			return fmt.Errorf("failed to grant access to the connector subnet: %w", err)
+		}
+	}
+
 	// Create VPC access connector for Cloud Run to reach Redis' private IP
-	connector, err := f.createVPCAccessConnector(ctx, instance.AuthorizedNetwork, args)
+	connector, err := f.createVPCAccessConnector(ctx, instance.AuthorizedNetwork, args, subnetGrants)
 	if err != nil {
 		return fmt.Errorf("failed to create VPC access connector: %w", err)
 	}
 
 	// Create firewall rule to allow Cloud Run to connect to Redis
-	firewall, err := f.createCacheFirewallRule(ctx, connector, instance.Port, instance.AuthorizedNetwork)
+	firewall, err := f.createCacheFirewallRule(ctx, connector, instance.Port, instance.AuthorizedNetwork, args)
 	if err != nil {
 		return fmt.Errorf("failed to create cache firewall rule: %w", err)
 	}
@@ -86,7 +95,10 @@ func (f *FullStack) enableRedisAPI(ctx *pulumi.Context) (*projects.Service, erro
 }
 
 // createVPCAccessConnector creates a Serverless VPC Access connector for Cloud Run to reach private resources
-func (f *FullStack) createVPCAccessConnector(ctx *pulumi.Context, cacheNetwork pulumi.StringOutput, args *CacheInstanceArgs) (*vpcaccess.Connector, error) {
+func (f *FullStack) createVPCAccessConnector(ctx *pulumi.Context,
+	cacheNetwork pulumi.StringOutput,
+	args *CacheInstanceArgs,
+	subnetGrants []pulumi.Resource) (*vpcaccess.Connector, error) {
 	// Enable VPC Access API
 	vpcAPI, err := projects.NewService(ctx, f.NewResourceName("cache", "vpcaccess-api", 63), &projects.ServiceArgs{
 		Project:                  pulumi.String(f.Project),
@@ -103,33 +115,69 @@ func (f *FullStack) createVPCAccessConnector(ctx *pulumi.Context, cacheNetwork p
 
 	connectorName := f.NewResourceName("cache", "vpc-connector", 25)
 
-	return vpcaccess.NewConnector(ctx, connectorName, &vpcaccess.ConnectorArgs{
-		Name:    pulumi.String(connectorName),
-		Project: pulumi.String(f.Project),
-		Region:  pulumi.String(f.Region),
-		Network: cacheNetwork.ApplyT(func(network string) pulumi.StringInput {
+	connectorArgs := &vpcaccess.ConnectorArgs{
+		Name:         pulumi.String(connectorName),
+		Project:      pulumi.String(f.Project),
+		Region:       pulumi.String(f.Region),
+		MinInstances: pulumi.Int(args.ConnectorMinInstances),
+		MaxInstances: pulumi.Int(args.ConnectorMaxInstances),
+	}
+
+	if args.ConnectorSubnet != "" {
+		// An existing /28 subnet, e.g. shared from the host project
+		connectorArgs.Subnet = &vpcaccess.ConnectorSubnetArgs{
+			Name:      pulumi.String(args.ConnectorSubnet),
+			ProjectId: pulumi.String(f.hostProject(args.NetworkProject)),
+		}
+	} else {
+		connectorArgs.Network = cacheNetwork.ApplyT(func(network string) pulumi.StringInput {
 			return pulumi.String(network)
-		}).(pulumi.StringInput),
-		IpCidrRange: pulumi.String(func() string {
+		}).(pulumi.StringInput)
+		connectorArgs.IpCidrRange = pulumi.String(func() string {
 			if args.ConnectorIPCidrRange == "" {
 				return "10.8.0.0/28" // fallback to default
 			}
 
 			return args.ConnectorIPCidrRange
-		}()),
-		MinInstances: pulumi.Int(args.ConnectorMinInstances),
-		MaxInstances: pulumi.Int(args.ConnectorMaxInstances),
-	}, pulumi.Parent(f), pulumi.DependsOn([]pulumi.Resource{vpcAPI}))
+		}())
+	}
+
+	return vpcaccess.NewConnector(ctx, connectorName, connectorArgs,
+		pulumi.Parent(f),
+		pulumi.DependsOn(append([]pulumi.Resource{vpcAPI}, subnetGrants...)),
+	)
 }
 
 // createCacheFirewallRule creates a firewall rule to allow Cloud Run to connect to Redis
 func (f *FullStack) createCacheFirewallRule(ctx *pulumi.Context, connector *vpcaccess.Connector,
 	instancePort pulumi.IntOutput,
-	cacheNetwork pulumi.StringOutput) (*compute.Firewall, error) {
+	cacheNetwork pulumi.StringOutput,
+	args *CacheInstanceArgs) (*compute.Firewall, error) {
+
+	// The connector only reports its IP range when it creates its own subnet
+	connectorIPRange := connector.IpCidrRange.ApplyT(func(ipCidrRange *string) string {
+		if ipCidrRange != nil {
+			return *ipCidrRange
+		}
+
+		if err := ctx.Log.Warn("No IP CIDR range found for connector, using fallback", nil); err != nil {
+			log.Printf("failed to log IP CIDR details with pulumi context: %v", err)
+		}
 
+		return "10.8.0.0/28" // fallback to default
+	}).(pulumi.StringOutput)
+	if args.ConnectorSubnet != "" {
+		connectorIPRange = compute.LookupSubnetworkOutput(ctx, compute.LookupSubnetworkOutputArgs{
+			Name:    pulumi.String(args.ConnectorSubnet),
+			Project: pulumi.String(f.hostProject(args.NetworkProject)),
+			Region:  pulumi.String(f.Region),
+		}).IpCidrRange()
+	}
+
+	// Firewall rules of a Shared VPC network belong to the host project
 	firewall, err := compute.NewFirewall(ctx, f.NewResourceName("cache", "firewall", 63), &compute.FirewallArgs{
 		Name:    pulumi.String(f.NewResourceName("cache", "allow-cloudrun-to-redis", 63)),
-		Project: pulumi.String(f.Project),
+		Project: pulumi.String(f.hostProject(args.NetworkProject)),
 		Network: cacheNetwork.ApplyT(func(network string) pulumi.StringInput {
 			return pulumi.String(network)
 		}).(pulumi.StringInput),
@@ -143,20 +191,8 @@ func (f *FullStack) createCacheFirewallRule(ctx *pulumi.Context, connector *vpca
 				}).(pulumi.StringOutput)},
 			},
 		},
-		SourceRanges: pulumi.StringArray{
-			connector.IpCidrRange.ApplyT(func(ipCidrRange *string) string {
-				if ipCidrRange != nil {
-					return *ipCidrRange
-				}
-
-				if err := ctx.Log.Warn("No IP CIDR range found for connector, using fallback", nil); err != nil {
-					log.Printf("failed to log IP CIDR details with pulumi context: %v", err)
-				}
-
-				return "10.8.0.0/28" // fallback to default
-			}).(pulumi.StringOutput),
-		},
-		Description: pulumi.String("Allow TCP on Redis instace port from Cloud Run VPC Connector subnet"),
+		SourceRanges: pulumi.StringArray{connectorIPRange},
+		Description:  pulumi.String("Allow TCP on Redis instace port from Cloud Run VPC Connector subnet"),
 	}, pulumi.Parent(f))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create cache firewall rule: %w", err)
@@ -165,12 +201,16 @@ func (f *FullStack) createCacheFirewallRule(ctx *pulumi.Context, connector *vpca
 	return firewall, nil
 }
 
-// createRedisInstance creates a Redis instance with auth and TLS enabled
+// createRedisInstance creates a Redis instance with auth and TLS enabled.
+//
+// Direct peering only works with a network of the instance project, so the instance connects
+// to a Shared VPC with private services access instead. It requires an allocated IP range and
+// a private services connection in the host network.
+//
+// See:
+// https://cloud.google.com/memorystore/docs/redis/networking#connection_modes
 func (f *FullStack) createRedisInstance(ctx *pulumi.Context, config *CacheInstanceArgs, redisAPI *projects.Service) (*redis.Instance, error) {
-	// Set defaults if not provided
-	applyCacheConfigDefaults(config)
-
-	return redis.NewInstance(ctx, f.NewResourceName("cache", "instance", 63), &redis.InstanceArgs{
+	instanceArgs := &redis.InstanceArgs{
 		Name:                  pulumi.String(f.NewResourceName("cache", "instance", 63)),
 		Project:               pulumi.String(f.Project),
 		Region:                pulumi.String(f.Region),
@@ -178,10 +218,15 @@ func (f *FullStack) createRedisInstance(ctx *pulumi.Context, config *CacheInstan
 		MemorySizeGb:          pulumi.Int(config.MemorySizeGb),
 		RedisVersion:          pulumi.String(config.RedisVersion),
 		Labels:                mergeLabels(f.Labels, pulumi.StringMap{"cache": pulumi.String("true")}),
-		AuthorizedNetwork:     pulumi.String(config.AuthorizedNetwork),
+		AuthorizedNetwork:     pulumi.String(networkPath(config.NetworkProject, config.AuthorizedNetwork)),
 		AuthEnabled:           pulumi.Bool(true),
 		TransitEncryptionMode: pulumi.String("SERVER_AUTHENTICATION"),
-	}, pulumi.Parent(f), pulumi.DependsOn([]pulumi.Resource{redisAPI}))
+	}
+	if config.NetworkProject != "" {
+		instanceArgs.ConnectMode = pulumi.String("PRIVATE_SERVICE_ACCESS")
+	}
+
+	return redis.NewInstance(ctx, f.NewResourceName("cache", "instance", 63), instanceArgs, pulumi.Parent(f), pulumi.DependsOn([]pulumi.Resource{redisAPI}))
 }
 
 // secureCacheCredentials stores Redis connection details in Secret Manager
@@ -266,6 +311,18 @@ func createDotEnvSecretData(instance *redis.Instance) pulumi.StringOutput {
 	}).(pulumi.StringOutput)
 }
 
+func validateCacheConfig(config *CacheInstanceArgs) error {
+	if config.NetworkProject != "" && config.ConnectorSubnet == "" {
+		return fmt.Errorf("connector subnet is required with a network project, since the connector can't create a subnet in it")
+	}
+
+	if config.ConnectorSubnet != "" && config.ConnectorIPCidrRange != "" {
+		return fmt.Errorf("connector IP CIDR range can't be set when using a connector subnet")
+	}
+
+	return nil
+}
+
 func applyCacheConfigDefaults(config *CacheInstanceArgs) {
 	if config.RedisVersion == "" {
 		config.RedisVersion = "REDIS_7_0"
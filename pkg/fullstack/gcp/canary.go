@@ -0,0 +1,174 @@
+package gcp
+
+import (
+	"fmt"
+
+	cloudrunv2 "github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/cloudrunv2"
+	compute "github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/compute"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// Internal upstream of the backend canary. It can't be set in the routing.
+const upstreamCanary = "canary"
+
+func validateCanary(args *CanaryArgs, network *NetworkArgs) error {
+	if args.Image == nil {
+		return fmt.Errorf("image is required")
+	}
+
+	if args.TrafficPercent < 0 || args.TrafficPercent > 100 {
+		return fmt.Errorf("traffic percent must be between 0 and 100, got %d", args.TrafficPercent)
+	}
+
+	if args.Header != nil {
+		if err := validateHeaderMatch(args.Header); err != nil {
+			return err
+		}
+	}
+
+	if !args.Promote && args.TrafficPercent == 0 && args.Header == nil {
+		return fmt.Errorf("traffic percent or header is required to route traffic to the canary")
+	}
+
+	if network == nil || network.EnableExternalWAF {
+		return fmt.Errorf("canary requires the load balancer")
+	}
+
+	// The gateway routes to the backend URL, out of reach of the URL map
+	if network.APIGateway != nil && !network.APIGateway.Disabled {
+		return fmt.Errorf("canary is not supported with API Gateway enabled")
+	}
+
+	// Traffic is split with route rules
+	if network.LoadBalancingScheme != LoadBalancingSchemeExternalManaged || usesRegionalLoadBalancer(network) {
+		return fmt.Errorf("canary requires the %s load balancing scheme with the global entrypoint", LoadBalancingSchemeExternalManaged)
+	}
+
+	return nil
+}
+
+// isCanaryActive returns true if the canary is deployed next to the stable backend.
+func isCanaryActive(args *CanaryArgs) bool {
+	return args != nil && !args.Promote
+}
+
+// backendImage returns the image of the stable backend, which is the canary one once promoted.
+func (f *FullStack) backendImage(args *BackendArgs) pulumi.StringInput {
+	if args.Canary != nil && args.Canary.Promote {
+		return args.Canary.Image
+	}
+
+	return f.BackendImage
+}
+
+// deployBackendCanary deploys the canary Cloud Run service with the template of the stable
+// backend, running the canary image in the main container.
+func (f *FullStack) deployBackendCanary(ctx *pulumi.Context,
+	args *BackendArgs,
+	serviceTemplate *cloudrunv2.ServiceTemplateArgs,
+	serviceArgs *cloudrunv2.ServiceArgs) (*cloudrunv2.Service, error) {
+	containers := serviceTemplate.Containers.(cloudrunv2.ServiceTemplateContainerArray)
+	mainContainer := *containers[0].(*cloudrunv2.ServiceTemplateContainerArgs)
+	mainContainer.Image = args.Canary.Image
+
+	canaryTemplate := *serviceTemplate
+	canaryTemplate.Containers = append(cloudrunv2.ServiceTemplateContainerArray{&mainContainer}, containers[1:]...)
+
+	canaryServiceName := f.NewResourceName(f.BackendName, "canary-service", 63)
+
+	canaryArgs := *serviceArgs
+	canaryArgs.Name = pulumi.String(canaryServiceName)
+	canaryArgs.Description = pulumi.String(fmt.Sprintf("Serverless canary instance (%s)", f.BackendName))
+	canaryArgs.Labels = mergeLabels(f.Labels, pulumi.StringMap{
+		"backend": pulumi.String("true"),
+		"canary":  pulumi.String("true"),
+	})
+	canaryArgs.Template = &canaryTemplate
+	// The canary is torn down when promoted or abandoned, unlike the stable backend
+	canaryArgs.DeletionProtection = pulumi.Bool(false)
+
+	canaryService, err := cloudrunv2.NewService(ctx, canaryServiceName, &canaryArgs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backend canary Cloud Run service: %w", err)
+	}
+
+	return canaryService, nil
+}
+
+// createCanaryNEG creates the serverless NEG of the backend canary and the load balancer
+// backend service routing to it, with the settings of the stable backend service.
+func (f *FullStack) createCanaryNEG(ctx *pulumi.Context,
+	serviceName string,
+	args *NetworkArgs,
+	backendServiceArgs *compute.BackendServiceArgs) (*compute.BackendService, error) {
+	canaryNegName := f.NewResourceName(serviceName, "backend-canary-cloudrun-neg", 63)
+	canaryNeg, err := compute.NewRegionNetworkEndpointGroup(ctx, canaryNegName, &compute.RegionNetworkEndpointGroupArgs{
+		Description:         pulumi.String(fmt.Sprintf("NEG to route LB traffic to the canary of %s", serviceName)),
+		Project:             pulumi.String(f.Project),
+		Region:              pulumi.String(f.Region),
+		NetworkEndpointType: pulumi.String("SERVERLESS"),
+		CloudRun: &compute.RegionNetworkEndpointGroupCloudRunArgs{
+			Service: f.backendCanaryService.Name,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backend canary Cloud Run NEG: %w", err)
+	}
+	f.backendCanaryNeg = canaryNeg
+
+	canaryServiceArgs := *backendServiceArgs
+	canaryServiceArgs.Description = pulumi.String(fmt.Sprintf("service canary for %s", serviceName))
+	canaryServiceArgs.Backends = compute.BackendServiceBackendArray{
+		&compute.BackendServiceBackendArgs{
+			Group: canaryNeg.SelfLink,
+		},
+	}
+
+	canaryServiceName := f.NewResourceName(serviceName, "cloudrun-backend-canary-service", 63)
+	canaryService, err := compute.NewBackendService(ctx, canaryServiceName, &canaryServiceArgs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backend canary service for NEG: %w", err)
+	}
+	f.backendCanaryLBService = canaryService
+
+	// The canary is protected like the stable backend
+	err = f.grantIAPAccess(ctx, fmt.Sprintf("%s-canary", serviceName), args, UpstreamBackend, canaryService)
+	if err != nil {
+		return nil, err
+	}
+
+	return canaryService, nil
+}
+
+// newCanaryRouteRules splits the traffic of the route rules routing to the backend: requests
+// matching the canary header go to the canary, and the rest is split by weight. Rules with
+// weighted upstreams keep their split.
+func newCanaryRouteRules(rules []*RouteRuleArgs, args *CanaryArgs) []*RouteRuleArgs {
+	canaryRules := make([]*RouteRuleArgs, 0, len(rules)*2)
+
+	for _, rule := range rules {
+		if rule.Upstream == UpstreamBackend && args.Header != nil {
+			headerRule := *rule
+			headerRule.Matches = withHeaderMatch(rule.Matches, args.Header)
+			headerRule.Upstream = upstreamCanary
+			canaryRules = append(canaryRules, &headerRule)
+		}
+
+		splitRule := *rule
+		if rule.Upstream == UpstreamBackend && args.TrafficPercent > 0 {
+			splitRule.Upstream = ""
+			splitRule.WeightedUpstreams = []*WeightedUpstreamArgs{
+				{Upstream: UpstreamBackend, Weight: 100 - args.TrafficPercent},
+				{Upstream: upstreamCanary, Weight: args.TrafficPercent},
+			}
+		}
+		canaryRules = append(canaryRules, &splitRule)
+	}
+
+	// Priorities follow the evaluation order
+	for index, rule := range canaryRules {
+		rule.Priority = index
+	}
+
+	return canaryRules
+}
@@ -0,0 +1,160 @@
+package gcp
+
+import (
+	"fmt"
+	"slices"
+
+	compute "github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/compute"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// Cloud CDN cache modes
+const (
+	CDNCacheAllStatic   = "CACHE_ALL_STATIC"
+	CDNUseOriginHeaders = "USE_ORIGIN_HEADERS"
+	CDNForceCacheAll    = "FORCE_CACHE_ALL"
+)
+
+// Cloud CDN TTL defaults and limits in seconds
+const (
+	defaultCDNDefaultTTL   = 3600
+	defaultCDNMaxTTL       = 86400
+	maxCDNTTL              = 31622400
+	maxCDNServeWhileStale  = 604800
+	maxCDNNegativeCacheTTL = 1800
+)
+
+var cdnCacheModes = []string{CDNCacheAllStatic, CDNUseOriginHeaders, CDNForceCacheAll}
+
+// Status codes Cloud CDN supports negative caching TTLs for
+var cdnNegativeCachingCodes = []int{300, 301, 302, 307, 308, 404, 405, 410, 421, 451, 501}
+
+func applyCDNDefaults(args *CDNArgs) {
+	if args.CacheMode == "" {
+		args.CacheMode = CDNCacheAllStatic
+	}
+
+	// TTLs are taken from the origin headers in USE_ORIGIN_HEADERS mode
+	if args.CacheMode == CDNUseOriginHeaders {
+		return
+	}
+
+	if args.DefaultTTL == 0 {
+		args.DefaultTTL = defaultCDNDefaultTTL
+	}
+
+	// The max TTL only caps the TTLs of the origin headers in CACHE_ALL_STATIC mode
+	if args.CacheMode == CDNCacheAllStatic && args.MaxTTL == 0 {
+		args.MaxTTL = max(defaultCDNMaxTTL, args.DefaultTTL)
+	}
+}
+
+func validateCDN(args *CDNArgs) error {
+	if !slices.Contains(cdnCacheModes, args.CacheMode) {
+		return fmt.Errorf("cache mode must be one of %v, got %q", cdnCacheModes, args.CacheMode)
+	}
+
+	if args.CacheMode == CDNUseOriginHeaders && (args.DefaultTTL != 0 || args.MaxTTL != 0) {
+		return fmt.Errorf("default and max TTLs can't be set in %s mode", CDNUseOriginHeaders)
+	}
+
+	if args.DefaultTTL < 0 || args.DefaultTTL > maxCDNTTL {
+		return fmt.Errorf("default TTL must be between 0 and %d seconds, got %d", maxCDNTTL, args.DefaultTTL)
+	}
+
+	if args.MaxTTL < 0 || args.MaxTTL > maxCDNTTL {
+		return fmt.Errorf("max TTL must be between 0 and %d seconds, got %d", maxCDNTTL, args.MaxTTL)
+	}
+
+	if args.CacheMode != CDNCacheAllStatic && args.MaxTTL != 0 {
+		return fmt.Errorf("max TTL can only be set in %s mode", CDNCacheAllStatic)
+	}
+
+	if args.CacheMode == CDNCacheAllStatic && args.MaxTTL < args.DefaultTTL {
+		return fmt.Errorf("max TTL %d must be greater than or equal to the default TTL %d", args.MaxTTL, args.DefaultTTL)
+	}
+
+	if args.CacheKey != nil && args.CacheKey.ExcludeQueryString && len(args.CacheKey.QueryStringAllowlist) > 0 {
+		return fmt.Errorf("query string allowlist can't be set when the query string is excluded from the cache key")
+	}
+
+	if args.ServeWhileStale < 0 || args.ServeWhileStale > maxCDNServeWhileStale {
+		return fmt.Errorf("serve while stale must be between 0 and %d seconds, got %d", maxCDNServeWhileStale, args.ServeWhileStale)
+	}
+
+	if len(args.NegativeCachingTTLs) > 0 && !args.NegativeCaching {
+		return fmt.Errorf("negative caching TTLs require negative caching to be enabled")
+	}
+
+	for code, ttl := range args.NegativeCachingTTLs {
+		if !slices.Contains(cdnNegativeCachingCodes, code) {
+			return fmt.Errorf("negative caching status code must be one of %v, got %d", cdnNegativeCachingCodes, code)
+		}
+		if ttl < 0 || ttl > maxCDNNegativeCacheTTL {
+			return fmt.Errorf("negative caching TTL for status code %d must be between 0 and %d seconds, got %d", code, maxCDNNegativeCacheTTL, ttl)
+		}
+	}
+
+	return nil
+}
+
+// newCDNPolicy returns the Cloud CDN policy for a load balancer backend service.
+//
+// See:
+// https://cloud.google.com/cdn/docs/caching
+func newCDNPolicy(args *CDNArgs) *compute.BackendServiceCdnPolicyArgs {
+	cacheKey := &CDNCacheKeyArgs{}
+	if args.CacheKey != nil {
+		cacheKey = args.CacheKey
+	}
+
+	cacheKeyPolicy := &compute.BackendServiceCdnPolicyCacheKeyPolicyArgs{
+		IncludeHost:        pulumi.Bool(!cacheKey.ExcludeHost),
+		IncludeProtocol:    pulumi.Bool(!cacheKey.ExcludeProtocol),
+		IncludeQueryString: pulumi.Bool(!cacheKey.ExcludeQueryString),
+	}
+	if len(cacheKey.QueryStringAllowlist) > 0 {
+		cacheKeyPolicy.QueryStringWhitelists = toStringArray(cacheKey.QueryStringAllowlist)
+	}
+	if len(cacheKey.IncludeHTTPHeaders) > 0 {
+		cacheKeyPolicy.IncludeHttpHeaders = toStringArray(cacheKey.IncludeHTTPHeaders)
+	}
+	if len(cacheKey.IncludeNamedCookies) > 0 {
+		cacheKeyPolicy.IncludeNamedCookies = toStringArray(cacheKey.IncludeNamedCookies)
+	}
+
+	cdnPolicy := &compute.BackendServiceCdnPolicyArgs{
+		CacheMode:       pulumi.String(args.CacheMode),
+		CacheKeyPolicy:  cacheKeyPolicy,
+		NegativeCaching: pulumi.Bool(args.NegativeCaching),
+		ServeWhileStale: pulumi.Int(args.ServeWhileStale),
+	}
+
+	if args.CacheMode != CDNUseOriginHeaders {
+		cdnPolicy.DefaultTtl = pulumi.Int(args.DefaultTTL)
+	}
+
+	if args.CacheMode == CDNCacheAllStatic {
+		cdnPolicy.MaxTtl = pulumi.Int(args.MaxTTL)
+	}
+
+	// Sort the status codes to keep the policy stable between deployments
+	codes := make([]int, 0, len(args.NegativeCachingTTLs))
+	for code := range args.NegativeCachingTTLs {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+
+	negativeCachingPolicies := compute.BackendServiceCdnPolicyNegativeCachingPolicyArray{}
+	for _, code := range codes {
+		negativeCachingPolicies = append(negativeCachingPolicies, &compute.BackendServiceCdnPolicyNegativeCachingPolicyArgs{
+			Code: pulumi.Int(code),
+			Ttl:  pulumi.Int(args.NegativeCachingTTLs[code]),
+		})
+	}
+	if len(negativeCachingPolicies) > 0 {
+		cdnPolicy.NegativeCachingPolicies = negativeCachingPolicies
+	}
+
+	return cdnPolicy
+}
@@ -0,0 +1,210 @@
+package gcp
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/certificatemanager"
+	"github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/dns"
+	"github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/projects"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+func applyCertificateManagerDefaults(args *CertificateManagerArgs, domains []string) {
+	if len(args.Domains) == 0 {
+		args.Domains = domains
+	}
+}
+
+// validateCertificateManager checks every load balancer domain is covered by the certificate.
+func validateCertificateManager(args *CertificateManagerArgs, domains []string) error {
+	for _, certDomain := range args.Domains {
+		if strings.Contains(strings.TrimPrefix(certDomain, "*."), "*") {
+			return fmt.Errorf("certificate domain %s can only have a leading wildcard", certDomain)
+		}
+	}
+
+	for _, domain := range domains {
+		if !certificateCoversDomain(args.Domains, domain) {
+			return fmt.Errorf("domain %s is not covered by the certificate domains %v", domain, args.Domains)
+		}
+	}
+
+	return nil
+}
+
+// certificateCoversDomain returns true if the domain matches a certificate domain
+// or a single-level wildcard of it.
+func certificateCoversDomain(certDomains []string, domain string) bool {
+	if slices.Contains(certDomains, domain) {
+		return true
+	}
+
+	_, parentDomain, found := strings.Cut(domain, ".")
+
+	return found && slices.Contains(certDomains, fmt.Sprintf("*.%s", parentDomain))
+}
+
+// authorizationDomains returns the distinct domains to authorize via DNS. Wildcard
+// domains are authorized by their parent domain.
+func authorizationDomains(certDomains []string) []string {
+	var domains []string
+	for _, certDomain := range certDomains {
+		domain := strings.TrimPrefix(certDomain, "*.")
+		if !slices.Contains(domains, domain) {
+			domains = append(domains, domain)
+		}
+	}
+
+	return domains
+}
+
+// newCertificateMap creates a Certificate Manager certificate authorized via DNS,
+// and a certificate map to attach it to the target HTTPS proxy.
+//
+// DNS authorization allows the certificate to be provisioned before DNS points at
+// the load balancer, and is required for wildcard certificates.
+//
+// See:
+// https://cloud.google.com/certificate-manager/docs/deploy-google-managed-dns-auth
+func (f *FullStack) newCertificateMap(ctx *pulumi.Context, serviceName string, args *CertificateManagerArgs) (*certificatemanager.CertificateMapResource, error) {
+	certificate, certManagerAPI, err := f.newCertificateManagerCertificate(ctx, serviceName, args, "")
+	if err != nil {
+		return nil, err
+	}
+
+	certificateMapName := f.NewResourceName(serviceName, "certificate-map", 63)
+	certificateMap, err := certificatemanager.NewCertificateMapResource(ctx, certificateMapName, &certificatemanager.CertificateMapResourceArgs{
+		Name:        pulumi.String(certificateMapName),
+		Description: pulumi.String(fmt.Sprintf("certificate map for %s", serviceName)),
+		Project:     pulumi.String(f.Project),
+		Labels:      mergeLabels(f.Labels, nil),
+	}, pulumi.DependsOn([]pulumi.Resource{certManagerAPI}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certificate map: %w", err)
+	}
+
+	// The certificate is served for any hostname reaching the proxy
+	certificateMapEntryName := f.NewResourceName(serviceName, "certificate-map-entry", 63)
+	_, err = certificatemanager.NewCertificateMapEntry(ctx, certificateMapEntryName, &certificatemanager.CertificateMapEntryArgs{
+		Name:        pulumi.String(certificateMapEntryName),
+		Description: pulumi.String(fmt.Sprintf("primary certificate map entry for %s", serviceName)),
+		Project:     pulumi.String(f.Project),
+		Map:         certificateMap.Name,
+		Matcher:     pulumi.String("PRIMARY"),
+		Certificates: pulumi.StringArray{
+			certificate.ID(),
+		},
+		Labels: mergeLabels(f.Labels, nil),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certificate map entry: %w", err)
+	}
+
+	f.certificateMap = certificateMap
+
+	return certificateMap, nil
+}
+
+// newCertificateManagerCertificate creates a Google-managed certificate authorized via DNS.
+// Regional load balancers can't use certificate maps, so the certificate is created in the
+// given region to be attached to the proxy directly. An empty region creates a global one.
+//
+// See:
+// https://cloud.google.com/certificate-manager/docs/deploy-google-managed-regional
+func (f *FullStack) newCertificateManagerCertificate(ctx *pulumi.Context, serviceName string, args *CertificateManagerArgs, region string) (*certificatemanager.Certificate, *projects.Service, error) {
+	certManagerAPIName := f.NewResourceName(serviceName, "certificatemanager-api", 63)
+	certManagerAPI, err := projects.NewService(ctx, certManagerAPIName, &projects.ServiceArgs{
+		Project: pulumi.String(f.Project),
+		Service: pulumi.String("certificatemanager.googleapis.com"),
+	},
+		pulumi.RetainOnDelete(true),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to enable Certificate Manager API: %w", err)
+	}
+
+	dnsAuthorizationIDs := pulumi.StringArray{}
+	for _, domain := range authorizationDomains(args.Domains) {
+		dnsAuthorization, err := f.newDNSAuthorization(ctx, serviceName, domain, region, certManagerAPI)
+		if err != nil {
+			return nil, nil, err
+		}
+		dnsAuthorizationIDs = append(dnsAuthorizationIDs, dnsAuthorization.ID())
+		f.dnsAuthorizations = append(f.dnsAuthorizations, dnsAuthorization)
+	}
+
+	certificateName := f.NewResourceName(serviceName, "certificate", 63)
+	certificateArgs := &certificatemanager.CertificateArgs{
+		Name:        pulumi.String(certificateName),
+		Description: pulumi.String(fmt.Sprintf("TLS cert for %s", serviceName)),
+		Project:     pulumi.String(f.Project),
+		Labels:      mergeLabels(f.Labels, nil),
+		Managed: &certificatemanager.CertificateManagedArgs{
+			Domains:           toStringArray(args.Domains),
+			DnsAuthorizations: dnsAuthorizationIDs,
+		},
+	}
+	if region != "" {
+		certificateArgs.Location = pulumi.String(region)
+	}
+
+	certificate, err := certificatemanager.NewCertificate(ctx, certificateName, certificateArgs,
+		pulumi.DependsOn([]pulumi.Resource{certManagerAPI}))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create Certificate Manager certificate: %w", err)
+	}
+	f.certificateManagerCertificate = certificate
+
+	return certificate, certManagerAPI, nil
+}
+
+// newDNSAuthorization creates a DNS authorization for the domain and its CNAME record
+// in the Cloud DNS managed zone matching the domain.
+func (f *FullStack) newDNSAuthorization(ctx *pulumi.Context, serviceName, domain, region string, certManagerAPI *projects.Service) (*certificatemanager.DnsAuthorization, error) {
+	dnsAuthorizationName := f.NewResourceName(serviceName, fmt.Sprintf("dns-auth-%s", resourceKey(domain)), 63)
+	dnsAuthorizationArgs := &certificatemanager.DnsAuthorizationArgs{
+		Name:        pulumi.String(dnsAuthorizationName),
+		Description: pulumi.String(fmt.Sprintf("DNS authorization for %s", domain)),
+		Project:     pulumi.String(f.Project),
+		Domain:      pulumi.String(domain),
+		Labels:      mergeLabels(f.Labels, nil),
+	}
+	if region != "" {
+		// Regional authorizations only support per-project records
+		dnsAuthorizationArgs.Location = pulumi.String(region)
+		dnsAuthorizationArgs.Type = pulumi.String("PER_PROJECT_RECORD")
+	}
+
+	dnsAuthorization, err := certificatemanager.NewDnsAuthorization(ctx, dnsAuthorizationName, dnsAuthorizationArgs,
+		pulumi.DependsOn([]pulumi.Resource{certManagerAPI}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DNS authorization for %s: %w", domain, err)
+	}
+
+	managedZoneName, err := f.lookupDNSZone(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each authorization comes with a single CNAME record to prove domain ownership
+	authorizationRecord := dnsAuthorization.DnsResourceRecords.Index(pulumi.Int(0))
+
+	dnsRecordName := f.NewResourceName(serviceName, fmt.Sprintf("dns-auth-record-%s", resourceKey(domain)), 63)
+	_, err = dns.NewRecordSet(ctx, dnsRecordName, &dns.RecordSetArgs{
+		Project:     pulumi.String(f.Project),
+		ManagedZone: pulumi.String(managedZoneName),
+		Name:        authorizationRecord.Name().Elem(),
+		Type:        authorizationRecord.Type().Elem(),
+		Ttl:         pulumi.Int(300),
+		Rrdatas: pulumi.StringArray{
+			authorizationRecord.Data().Elem(),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DNS authorization record for %s: %w", domain, err)
+	}
+
+	return dnsAuthorization, nil
+}
@@ -0,0 +1,196 @@
+package gcp
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	compute "github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/compute"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+const (
+	cloudArmorRuleActionRedirect = "redirect"
+
+	cloudArmorRedirectExternal  = "EXTERNAL_302"
+	cloudArmorRedirectRecaptcha = "GOOGLE_RECAPTCHA"
+
+	// Max source IP ranges of a rule
+	maxCloudArmorRuleSourceIPRanges = 10
+)
+
+// Actions of the custom rules. Rate limits have their own rules.
+var cloudArmorRuleActions = []string{"allow", "deny(403)", "deny(404)", "deny(429)", "deny(502)", cloudArmorRuleActionRedirect}
+
+// reservedPriorityRange is a range of priorities of the generated Cloud Armor rules. They're
+// reserved even when the feature generating them is disabled, so enabling it can't collide
+// with the custom rules.
+type reservedPriorityRange struct {
+	first int
+	last  int
+	owner string
+}
+
+var reservedRulePriorities = []reservedPriorityRange{
+	{ipAllowlistRulesBasePriority, ipAllowlistRulesBasePriority + 1, "client IP allowlists"},
+	{autoDeployRulePriority, autoDeployRulePriority, "Adaptive Protection auto-deploy rule"},
+	{preconfiguredRulesBasePriority, preconfiguredRulesBasePriority + len(preconfiguredWAFRules) - 1, "preconfigured WAF rules"},
+	{botManagementRulesBasePriority, botManagementRulesBasePriority + maxBotManagementPaths - 1, "bot management"},
+	{rateLimitRulesBasePriority, rateLimitRulesBasePriority + maxRateLimits - 1, "rate limits"},
+	{defaultRulePriority, defaultRulePriority, "default rule"},
+}
+
+func validateCloudArmorRules(args *NetworkArgs) error {
+	if !args.EnableCloudArmor {
+		return fmt.Errorf("custom rules require Cloud Armor to be enabled")
+	}
+
+	// Priority 0 is the only one evaluated before the client IP allowlists, so it's left to the
+	// custom rules unless the geo access rule takes it
+	reservedPriorities := reservedRulePriorities
+	if args.GeoAccess != nil {
+		reservedPriorities = append([]reservedPriorityRange{
+			{geoAccessRulePriority, geoAccessRulePriority, "geo access rule"},
+		}, reservedRulePriorities...)
+	}
+
+	seenPriorities := map[int]int{}
+	for index, rule := range args.CloudArmorRules {
+		if rule == nil {
+			return fmt.Errorf("rule %d is empty", index)
+		}
+
+		if err := validateCloudArmorRule(rule, reservedPriorities); err != nil {
+			return fmt.Errorf("rule %d: %w", index, err)
+		}
+
+		if previous, ok := seenPriorities[rule.Priority]; ok {
+			return fmt.Errorf("rule %d: priority %d is already used by rule %d", index, rule.Priority, previous)
+		}
+		seenPriorities[rule.Priority] = index
+	}
+
+	return nil
+}
+
+func validateCloudArmorRule(rule *CloudArmorRuleArgs, reservedPriorities []reservedPriorityRange) error {
+	if rule.Priority < 0 || rule.Priority >= defaultRulePriority {
+		return fmt.Errorf("priority must be between 0 and %d, got %d", defaultRulePriority-1, rule.Priority)
+	}
+
+	for _, reserved := range reservedPriorities {
+		if rule.Priority < reserved.first || rule.Priority > reserved.last {
+			continue
+		}
+
+		if reserved.first == reserved.last {
+			return fmt.Errorf("priority %d is reserved for the %s", rule.Priority, reserved.owner)
+		}
+
+		return fmt.Errorf("priority %d is reserved for the %s (%d-%d)", rule.Priority, reserved.owner, reserved.first, reserved.last)
+	}
+
+	if (rule.Expression == "") == (len(rule.SourceIPRanges) == 0) {
+		return fmt.Errorf("exactly one of expression or source IP ranges is required")
+	}
+
+	if len(rule.SourceIPRanges) > maxCloudArmorRuleSourceIPRanges {
+		return fmt.Errorf("at most %d source IP ranges are supported, got %d", maxCloudArmorRuleSourceIPRanges, len(rule.SourceIPRanges))
+	}
+
+	for _, ipRange := range rule.SourceIPRanges {
+		if ipRange == "*" || net.ParseIP(ipRange) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(ipRange); err != nil {
+			return fmt.Errorf("source IP range %q must be an IP address, a CIDR range or *", ipRange)
+		}
+	}
+
+	if !slices.Contains(cloudArmorRuleActions, rule.Action) {
+		return fmt.Errorf("action must be one of %v, got %q", cloudArmorRuleActions, rule.Action)
+	}
+
+	if rule.Action != cloudArmorRuleActionRedirect {
+		if rule.Redirect != nil {
+			return fmt.Errorf("redirect is only supported with the %q action", cloudArmorRuleActionRedirect)
+		}
+
+		return nil
+	}
+
+	return validateCloudArmorRedirect(rule.Redirect)
+}
+
+func validateCloudArmorRedirect(redirect *CloudArmorRedirectArgs) error {
+	if redirect == nil {
+		return fmt.Errorf("redirect is required with the %q action", cloudArmorRuleActionRedirect)
+	}
+
+	switch redirect.Type {
+	case cloudArmorRedirectExternal:
+		if !strings.HasPrefix(redirect.Target, "https://") && !strings.HasPrefix(redirect.Target, "http://") {
+			return fmt.Errorf("redirect target %q must be an http or https URL", redirect.Target)
+		}
+	case cloudArmorRedirectRecaptcha:
+		if redirect.Target != "" {
+			return fmt.Errorf("redirect target is not supported with %s", cloudArmorRedirectRecaptcha)
+		}
+	default:
+		return fmt.Errorf("redirect type must be %q or %q, got %q", cloudArmorRedirectExternal, cloudArmorRedirectRecaptcha, redirect.Type)
+	}
+
+	return nil
+}
+
+// newCustomRules creates the user-defined Cloud Armor rules. Rules in preview are logged
+// without being enforced.
+//
+// See:
+// https://cloud.google.com/armor/docs/configure-security-policies#create-rules
+func newCustomRules(cloudArmorRules []*CloudArmorRuleArgs) compute.SecurityPolicyRuleTypeArray {
+	var customRules compute.SecurityPolicyRuleTypeArray
+	for _, rule := range cloudArmorRules {
+		description := rule.Description
+		if description == "" {
+			description = fmt.Sprintf("custom rule %d", rule.Priority)
+		}
+
+		customRule := &compute.SecurityPolicyRuleTypeArgs{
+			Action:      pulumi.String(rule.Action),
+			Description: pulumi.String(description),
+			Priority:    pulumi.Int(rule.Priority),
+			Preview:     pulumi.Bool(rule.Preview),
+		}
+
+		if rule.Expression != "" {
+			customRule.Match = &compute.SecurityPolicyRuleMatchArgs{
+				Expr: &compute.SecurityPolicyRuleMatchExprArgs{
+					Expression: pulumi.String(rule.Expression),
+				},
+			}
+		} else {
+			customRule.Match = &compute.SecurityPolicyRuleMatchArgs{
+				VersionedExpr: pulumi.String("SRC_IPS_V1"),
+				Config: &compute.SecurityPolicyRuleMatchConfigArgs{
+					SrcIpRanges: toStringArray(rule.SourceIPRanges),
+				},
+			}
+		}
+
+		if rule.Redirect != nil {
+			redirectOptions := &compute.SecurityPolicyRuleRedirectOptionsArgs{
+				Type: pulumi.String(rule.Redirect.Type),
+			}
+			if rule.Redirect.Target != "" {
+				redirectOptions.Target = pulumi.String(rule.Redirect.Target)
+			}
+			customRule.RedirectOptions = redirectOptions
+		}
+
+		customRules = append(customRules, customRule)
+	}
+
+	return customRules
+}
@@ -146,7 +146,7 @@ func (f *FullStack) deployBackendCloudRunInstance(ctx *pulumi.Context, args *Bac
 
 	containers := cloudrunv2.ServiceTemplateContainerArray{
 		&cloudrunv2.ServiceTemplateContainerArgs{
-			Image: f.BackendImage,
+			Image: f.backendImage(args),
 			Envs:  newBackendEnvVars(args, f.AppBaseURL),
 			Resources: &cloudrunv2.ServiceTemplateContainerResourcesArgs{
 				CpuIdle:         pulumi.Bool(true),
@@ -181,6 +181,7 @@ func (f *FullStack) deployBackendCloudRunInstance(ctx *pulumi.Context, args *Bac
 		Containers:     containers,
 		ServiceAccount: serviceAccount.Email,
 		Volumes:        volumes,
+		Timeout:        requestTimeout(args.LoadBalancerService),
 	}
 
 	if f.vpcConnector != nil {
@@ -197,7 +198,7 @@ func (f *FullStack) deployBackendCloudRunInstance(ctx *pulumi.Context, args *Bac
 		ingress = "INGRESS_TRAFFIC_ALL"
 	}
 
-	backendService, err := cloudrunv2.NewService(ctx, backendServiceName, &cloudrunv2.ServiceArgs{
+	serviceArgs := &cloudrunv2.ServiceArgs{
 		Name:               pulumi.String(backendServiceName),
 		Ingress:            pulumi.String(ingress),
 		Description:        pulumi.String(fmt.Sprintf("Serverless instance (%s)", backendName)),
@@ -206,11 +207,21 @@ func (f *FullStack) deployBackendCloudRunInstance(ctx *pulumi.Context, args *Bac
 		Labels:             backendLabels,
 		Template:           serviceTemplate,
 		DeletionProtection: pulumi.Bool(args.DeletionProtection),
-	})
+	}
+
+	backendService, err := cloudrunv2.NewService(ctx, backendServiceName, serviceArgs)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create backend Cloud Run service: %w", err)
 	}
 
+	if isCanaryActive(args.Canary) {
+		canaryService, err := f.deployBackendCanary(ctx, args, serviceTemplate, serviceArgs)
+		if err != nil {
+			return nil, nil, err
+		}
+		f.backendCanaryService = canaryService
+	}
+
 	err = f.grantProjectLevelIAMRoles(ctx, args.ProjectIAMRoles, backendServiceName, serviceAccount)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to grant project level IAM roles to backend Cloud Run service: %w", err)
@@ -490,6 +501,15 @@ func (f *FullStack) deployFrontendCloudRunInstance(ctx *pulumi.Context, args *Fr
 
 	frontendServiceName := f.NewResourceName(serviceName, "service", 63)
 
+	envVars := newFrontendEnvVars(args, backendURL, f.AppBaseURL)
+	if f.recaptchaSessionTokenKey != nil {
+		// The key name is the site key loading reCAPTCHA in the browser
+		envVars = append(envVars, cloudrunv2.ServiceTemplateContainerEnvArgs{
+			Name:  pulumi.String(RECAPTCHA_SITE_KEY),
+			Value: f.recaptchaSessionTokenKey.Name,
+		})
+	}
+
 	containers := cloudrunv2.ServiceTemplateContainerArray{
 		&cloudrunv2.ServiceTemplateContainerArgs{
 			Image: frontendImage,
@@ -503,7 +523,7 @@ func (f *FullStack) deployFrontendCloudRunInstance(ctx *pulumi.Context, args *Fr
 				ContainerPort: pulumi.Int(args.ContainerPort),
 			},
 
-			Envs:          newFrontendEnvVars(args, backendURL, f.AppBaseURL),
+			Envs:          envVars,
 			StartupProbe:  startupProbe(args.ContainerPort, args.StartupProbe),
 			LivenessProbe: livenessProbe(args.ContainerPort, args.LivenessProbe.Path, args.LivenessProbe),
 			VolumeMounts:  volumeMounts,
@@ -529,6 +549,7 @@ func (f *FullStack) deployFrontendCloudRunInstance(ctx *pulumi.Context, args *Fr
 		Containers:     containers,
 		ServiceAccount: serviceAccount.Email,
 		Volumes:        volumes,
+		Timeout:        requestTimeout(args.LoadBalancerService),
 	}
 
 	ingress := "INGRESS_TRAFFIC_INTERNAL_LOAD_BALANCER"
@@ -591,6 +612,19 @@ func (f *FullStack) createCloudRunInstancesIAM(ctx *pulumi.Context, frontendServ
 		return fmt.Errorf("failed to grant backend invoker: %w", err)
 	}
 
+	if f.backendCanaryService != nil {
+		_, err = cloudrunv2.NewServiceIamMember(ctx, fmt.Sprintf("%s-canary-allow-unauthenticated", f.BackendName), &cloudrunv2.ServiceIamMemberArgs{
+			Name:     f.backendCanaryService.Name,
+			Project:  pulumi.String(f.Project),
+			Location: pulumi.String(f.Region),
+			Role:     pulumi.String("roles/run.invoker"),
+			Member:   pulumi.Sprintf("allUsers"),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to grant backend canary invoker: %w", err)
+		}
+	}
+
 	return nil
 }
 
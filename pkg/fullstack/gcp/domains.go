@@ -0,0 +1,114 @@
+package gcp
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+const (
+	// Google-managed certificates support up to 100 domains
+	maxManagedCertificateDomains = 100
+
+	maxHostnameLength = 253
+)
+
+// Lowercase RFC 1123 hostname of at least two labels. Wildcards aren't supported, since every
+// domain gets a host rule and DNS records of its own.
+var hostnamePattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// lbDomains returns the domain URL followed by the additional domains, without duplicates.
+func lbDomains(args *NetworkArgs) []string {
+	domains := []string{args.DomainURL}
+	for _, domain := range args.AdditionalDomains {
+		if !slices.Contains(domains, domain) {
+			domains = append(domains, domain)
+		}
+	}
+
+	return domains
+}
+
+// certificateDomains returns all the domains reaching the load balancer: the app domains
+// followed by the API domain if set. They all get a certificate SAN and DNS records.
+func certificateDomains(args *NetworkArgs) []string {
+	domains := lbDomains(args)
+	if args.APIDomain != "" {
+		domains = append(domains, args.APIDomain)
+	}
+
+	return domains
+}
+
+// servedDomains returns the domains routed to the upstreams. When a canonical
+// domain is set, all the other domains get redirected to it instead.
+func servedDomains(args *NetworkArgs) []string {
+	if args.CanonicalDomain != "" {
+		return []string{args.CanonicalDomain}
+	}
+
+	return lbDomains(args)
+}
+
+// redirectedDomains returns the domains to redirect to the canonical domain.
+func redirectedDomains(args *NetworkArgs) []string {
+	if args.CanonicalDomain == "" {
+		return nil
+	}
+
+	var domains []string
+	for _, domain := range lbDomains(args) {
+		if domain != args.CanonicalDomain {
+			domains = append(domains, domain)
+		}
+	}
+
+	return domains
+}
+
+func validateDomains(args *NetworkArgs) error {
+	if args.DomainURL == "" {
+		return fmt.Errorf("domain URL is required")
+	}
+
+	if err := validateHostname(args.DomainURL); err != nil {
+		return err
+	}
+
+	for _, domain := range args.AdditionalDomains {
+		if err := validateHostname(domain); err != nil {
+			return fmt.Errorf("invalid additional domain: %w", err)
+		}
+	}
+
+	if args.APIDomain != "" {
+		if err := validateHostname(args.APIDomain); err != nil {
+			return fmt.Errorf("invalid API domain: %w", err)
+		}
+		if slices.Contains(lbDomains(args), args.APIDomain) {
+			return fmt.Errorf("API domain %s must be different from the app domains", args.APIDomain)
+		}
+		if args.APIGateway != nil && !args.APIGateway.Disabled {
+			return fmt.Errorf("API domain is not supported with API Gateway enabled")
+		}
+	}
+
+	domains := certificateDomains(args)
+	if len(domains) > maxManagedCertificateDomains {
+		return fmt.Errorf("at most %d domains are supported, got %d", maxManagedCertificateDomains, len(domains))
+	}
+
+	if args.CanonicalDomain != "" && !slices.Contains(lbDomains(args), args.CanonicalDomain) {
+		return fmt.Errorf("canonical domain %s must be the domain URL or one of the additional domains", args.CanonicalDomain)
+	}
+
+	return nil
+}
+
+func validateHostname(domain string) error {
+	if len(domain) > maxHostnameLength || !hostnamePattern.MatchString(domain) {
+		return fmt.Errorf("domain %q must be a lowercase hostname without wildcards, e.g. \"myapp.example.com\"", domain)
+	}
+
+	return nil
+}
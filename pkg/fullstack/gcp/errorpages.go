@@ -0,0 +1,297 @@
+package gcp
+
+import (
+	"fmt"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	compute "github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/compute"
+	"github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/storage"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// Status code ranges an error page can match besides single codes
+var errorStatusCodeRanges = []string{"4xx", "5xx"}
+
+func validateErrorPages(args *NetworkArgs) error {
+	errorPages := args.ErrorPages
+
+	if args.LoadBalancingScheme != LoadBalancingSchemeExternalManaged {
+		return fmt.Errorf("custom error pages require the %s load balancing scheme", LoadBalancingSchemeExternalManaged)
+	}
+
+	if errorPages.Directory == "" {
+		return fmt.Errorf("directory is required")
+	}
+
+	info, err := os.Stat(errorPages.Directory)
+	if err != nil {
+		return fmt.Errorf("failed to read directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", errorPages.Directory)
+	}
+
+	if len(errorPages.Backend) == 0 && len(errorPages.Frontend) == 0 {
+		return fmt.Errorf("at least one backend or frontend error page is required")
+	}
+
+	for _, upstream := range []string{UpstreamBackend, UpstreamFrontend} {
+		if err := validateUpstreamErrorPages(errorPages.Directory, upstreamErrorPages(errorPages, upstream)); err != nil {
+			return fmt.Errorf("invalid %s error pages: %w", upstream, err)
+		}
+	}
+
+	return nil
+}
+
+func validateUpstreamErrorPages(directory string, pages []*ErrorPageArgs) error {
+	seenCodes := map[string]bool{}
+
+	for index, page := range pages {
+		if page == nil || len(page.StatusCodes) == 0 {
+			return fmt.Errorf("error page %d must match at least one status code", index)
+		}
+
+		for _, code := range page.StatusCodes {
+			if err := validateErrorStatusCode(code); err != nil {
+				return fmt.Errorf("error page %d: %w", index, err)
+			}
+			if seenCodes[code] {
+				return fmt.Errorf("status code %s is mapped more than once", code)
+			}
+			seenCodes[code] = true
+		}
+
+		if !strings.HasPrefix(page.Page, "/") || strings.HasSuffix(page.Page, "/") {
+			return fmt.Errorf("error page %d: page %q must start with / and not end with /", index, page.Page)
+		}
+
+		info, err := os.Stat(filepath.Join(directory, filepath.FromSlash(page.Page)))
+		if err != nil {
+			return fmt.Errorf("error page %d: page %s not found in %s", index, page.Page, directory)
+		}
+		if info.IsDir() {
+			return fmt.Errorf("error page %d: page %s is a directory", index, page.Page)
+		}
+
+		if page.OverrideStatusCode != 0 && (page.OverrideStatusCode < 200 || page.OverrideStatusCode > 599) {
+			return fmt.Errorf("error page %d: override status code must be between 200 and 599, got %d", index, page.OverrideStatusCode)
+		}
+	}
+
+	return nil
+}
+
+func validateErrorStatusCode(code string) error {
+	for _, codeRange := range errorStatusCodeRanges {
+		if code == codeRange {
+			return nil
+		}
+	}
+
+	status, err := strconv.Atoi(code)
+	if err != nil || status < 400 || status > 599 {
+		return fmt.Errorf("status code must be between 400 and 599 or one of %v, got %q", errorStatusCodeRanges, code)
+	}
+
+	return nil
+}
+
+// newErrorPagesBackendBucket uploads the files of the error pages directory to a public bucket
+// and creates the backend bucket the URL map serves the custom error responses from.
+//
+// See:
+// https://cloud.google.com/load-balancing/docs/https/custom-error-responses
+func (f *FullStack) newErrorPagesBackendBucket(ctx *pulumi.Context, serviceName string, args *ErrorPagesArgs) (*compute.BackendBucket, error) {
+	bucketName := f.NewResourceName(serviceName, "error-pages", 63)
+	bucket, err := storage.NewBucket(ctx, bucketName, &storage.BucketArgs{
+		Name:     pulumi.String(bucketName),
+		Project:  pulumi.String(f.Project),
+		Location: pulumi.String(f.Region),
+		Labels: mergeLabels(f.Labels, pulumi.StringMap{
+			"load_balancer": pulumi.String("true"),
+		}),
+		ForceDestroy:             pulumi.Bool(true),
+		UniformBucketLevelAccess: pulumi.Bool(true),
+	}, pulumi.Parent(f))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create error pages bucket: %w", err)
+	}
+	f.errorPagesBucket = bucket
+
+	err = filepath.WalkDir(args.Directory, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(args.Directory, filePath)
+		if err != nil {
+			return err
+		}
+		objectName := filepath.ToSlash(relativePath)
+
+		objectArgs := &storage.BucketObjectArgs{
+			Bucket: bucket.Name,
+			Name:   pulumi.String(objectName),
+			Source: pulumi.NewFileAsset(filePath),
+		}
+		if contentType := mime.TypeByExtension(path.Ext(objectName)); contentType != "" {
+			objectArgs.ContentType = pulumi.String(contentType)
+		}
+
+		_, err = storage.NewBucketObject(ctx, fmt.Sprintf("%s-%s", bucketName, objectName), objectArgs, pulumi.Parent(f))
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload error pages: %w", err)
+	}
+
+	// Backend buckets read the objects anonymously
+	_, err = storage.NewBucketIAMMember(ctx, fmt.Sprintf("%s-public", bucketName), &storage.BucketIAMMemberArgs{
+		Bucket: bucket.Name,
+		Role:   pulumi.String("roles/storage.objectViewer"),
+		Member: pulumi.String("allUsers"),
+	}, pulumi.Parent(f))
+	if err != nil {
+		return nil, fmt.Errorf("failed to grant public access to error pages bucket: %w", err)
+	}
+
+	backendBucketName := f.NewResourceName(serviceName, "error-pages-backend", 63)
+	backendBucket, err := compute.NewBackendBucket(ctx, backendBucketName, &compute.BackendBucketArgs{
+		Description: pulumi.String(fmt.Sprintf("custom error pages of %s", serviceName)),
+		Project:     pulumi.String(f.Project),
+		BucketName:  bucket.Name,
+		EnableCdn:   pulumi.Bool(false),
+	}, pulumi.Parent(f))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create error pages backend bucket: %w", err)
+	}
+	f.errorPagesBackendBucket = backendBucket
+
+	return backendBucket, nil
+}
+
+// upstreamErrorPages returns the error pages of the responses of an upstream.
+func upstreamErrorPages(args *ErrorPagesArgs, upstream string) []*ErrorPageArgs {
+	if upstream == UpstreamFrontend {
+		return args.Frontend
+	}
+
+	return args.Backend
+}
+
+// newErrorResponsePolicy creates the URL map custom error response policy of an upstream.
+func newErrorResponsePolicy(args *ErrorPagesArgs, upstream string, errorService pulumi.StringOutput) *compute.URLMapDefaultCustomErrorResponsePolicyArgs {
+	rules := compute.URLMapDefaultCustomErrorResponsePolicyErrorResponseRuleArray{}
+	for _, page := range upstreamErrorPages(args, upstream) {
+		rules = append(rules, &compute.URLMapDefaultCustomErrorResponsePolicyErrorResponseRuleArgs{
+			MatchResponseCodes:   toStringArray(page.StatusCodes),
+			Path:                 pulumi.String(page.Page),
+			OverrideResponseCode: overrideStatusCode(page),
+		})
+	}
+
+	return &compute.URLMapDefaultCustomErrorResponsePolicyArgs{
+		ErrorResponseRules: rules,
+		ErrorService:       errorService,
+	}
+}
+
+// applyErrorPages sets the custom error response policies of a path matcher: its path and route
+// rules get the pages of the upstream they route to, and the rest the pages of its default
+// upstream. An upstream without pages still gets a policy without rules, so it doesn't inherit
+// the pages of the default upstream.
+func applyErrorPages(pathMatcher *compute.URLMapPathMatcherArgs,
+	rules []*PathRuleArgs,
+	routeRules []*RouteRuleArgs,
+	defaultUpstream string,
+	args *ErrorPagesArgs,
+	errorService pulumi.StringOutput) {
+	defaultRules := compute.URLMapPathMatcherDefaultCustomErrorResponsePolicyErrorResponseRuleArray{}
+	for _, page := range upstreamErrorPages(args, defaultUpstream) {
+		defaultRules = append(defaultRules, &compute.URLMapPathMatcherDefaultCustomErrorResponsePolicyErrorResponseRuleArgs{
+			MatchResponseCodes:   toStringArray(page.StatusCodes),
+			Path:                 pulumi.String(page.Page),
+			OverrideResponseCode: overrideStatusCode(page),
+		})
+	}
+	pathMatcher.DefaultCustomErrorResponsePolicy = &compute.URLMapPathMatcherDefaultCustomErrorResponsePolicyArgs{
+		ErrorResponseRules: defaultRules,
+		ErrorService:       errorService,
+	}
+
+	// Path rules are created in the order they're declared in
+	pathRules, _ := pathMatcher.PathRules.(compute.URLMapPathMatcherPathRuleArray)
+	for index, pathRule := range pathRules {
+		upstreamRules := compute.URLMapPathMatcherPathRuleCustomErrorResponsePolicyErrorResponseRuleArray{}
+		for _, page := range upstreamErrorPages(args, rules[index].Upstream) {
+			upstreamRules = append(upstreamRules, &compute.URLMapPathMatcherPathRuleCustomErrorResponsePolicyErrorResponseRuleArgs{
+				MatchResponseCodes:   toStringArray(page.StatusCodes),
+				Path:                 pulumi.String(page.Page),
+				OverrideResponseCode: overrideStatusCode(page),
+			})
+		}
+		pathRule.(*compute.URLMapPathMatcherPathRuleArgs).CustomErrorResponsePolicy = &compute.URLMapPathMatcherPathRuleCustomErrorResponsePolicyArgs{
+			ErrorResponseRules: upstreamRules,
+			ErrorService:       errorService,
+		}
+	}
+
+	// Route rules are created in the order they're declared in too
+	pathMatcherRouteRules, _ := pathMatcher.RouteRules.(compute.URLMapPathMatcherRouteRuleArray)
+	for index, routeRule := range pathMatcherRouteRules {
+		upstreamRules := compute.URLMapPathMatcherRouteRuleCustomErrorResponsePolicyErrorResponseRuleArray{}
+		for _, page := range upstreamErrorPages(args, routeRuleUpstream(routeRules[index], defaultUpstream)) {
+			upstreamRules = append(upstreamRules, &compute.URLMapPathMatcherRouteRuleCustomErrorResponsePolicyErrorResponseRuleArgs{
+				MatchResponseCodes:   toStringArray(page.StatusCodes),
+				Path:                 pulumi.String(page.Page),
+				OverrideResponseCode: overrideStatusCode(page),
+			})
+		}
+		routeRule.(*compute.URLMapPathMatcherRouteRuleArgs).CustomErrorResponsePolicy = &compute.URLMapPathMatcherRouteRuleCustomErrorResponsePolicyArgs{
+			ErrorResponseRules: upstreamRules,
+			ErrorService:       errorService,
+		}
+	}
+}
+
+// routeRuleUpstream returns the upstream whose error pages a route rule gets. The canary gets
+// the pages of the backend, and rules splitting the traffic across the backend and the frontend
+// get the pages of the default upstream.
+func routeRuleUpstream(rule *RouteRuleArgs, defaultUpstream string) string {
+	pagesUpstream := func(upstream string) string {
+		if upstream == upstreamCanary {
+			return UpstreamBackend
+		}
+
+		return upstream
+	}
+
+	if rule.Upstream != "" {
+		return pagesUpstream(rule.Upstream)
+	}
+
+	upstream := pagesUpstream(rule.WeightedUpstreams[0].Upstream)
+	for _, weighted := range rule.WeightedUpstreams[1:] {
+		if pagesUpstream(weighted.Upstream) != upstream {
+			return defaultUpstream
+		}
+	}
+
+	return upstream
+}
+
+func overrideStatusCode(page *ErrorPageArgs) pulumi.IntPtrInput {
+	if page.OverrideStatusCode == 0 {
+		return nil
+	}
+
+	return pulumi.IntPtr(page.OverrideStatusCode)
+}
@@ -6,11 +6,13 @@ import (
 
 	namer "github.com/davidmontoyago/commodity-namer"
 	apigateway "github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/apigateway"
+	"github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/certificatemanager"
 	"github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/cloudrun"
 	cloudrunv2 "github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/cloudrunv2"
 	"github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/compute"
 	"github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/dns"
 	"github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/projects"
+	"github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/recaptcha"
 	"github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/redis"
 	"github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/secretmanager"
 	"github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/serviceaccount"
@@ -35,11 +37,24 @@ type FullStack struct {
 
 	gatewayEnabled      bool
 	loadBalancerEnabled bool
+	// Load balancing scheme and migration state of the external load balancer
+	loadBalancingScheme      string
+	externalManagedMigration *ExternalManagedMigrationArgs
+	// Load balancer backend service settings of each upstream
+	backendLBServiceArgs  *LoadBalancerServiceArgs
+	frontendLBServiceArgs *LoadBalancerServiceArgs
+	// Canary release of the backend
+	backendCanaryArgs *CanaryArgs
 
 	backendService      *cloudrunv2.Service
 	backendAccount      *serviceaccount.Account
 	backendColdStartSLO *ColdStartSLO
 
+	// Backend canary service, NEG and load balancer backend service. Nil unless a canary is active.
+	backendCanaryService   *cloudrunv2.Service
+	backendCanaryNeg       *compute.RegionNetworkEndpointGroup
+	backendCanaryLBService *compute.BackendService
+
 	frontendService      *cloudrunv2.Service
 	frontendAccount      *serviceaccount.Account
 	frontendColdStartSLO *ColdStartSLO
@@ -60,6 +75,20 @@ type FullStack struct {
 	backendNeg  *compute.RegionNetworkEndpointGroup
 	frontendNeg *compute.RegionNetworkEndpointGroup
 
+	// Proxy-only subnet for Envoy-based load balancers. Nil for classic or when reusing an existing one.
+	proxySubnet *compute.Subnetwork
+
+	// Load balancer backend services routing to the Cloud Run NEGs
+	backendLBService  *compute.BackendService
+	frontendLBService *compute.BackendService
+
+	// Regional external load balancer resources, in place of the global ones
+	regionBackendLBService  *compute.RegionBackendService
+	regionFrontendLBService *compute.RegionBackendService
+	regionURLMap            *compute.RegionUrlMap
+	regionSSLCertificate    *compute.RegionSslCertificate
+	regionSSLPolicy         *compute.RegionSslPolicy
+
 	// Domain mappings to use when the external LB is disabled and External WAF is used
 	backendDomainMapping  *cloudrun.DomainMapping
 	frontendDomainMapping *cloudrun.DomainMapping
@@ -67,9 +96,54 @@ type FullStack struct {
 	globalForwardingRule   *compute.GlobalForwardingRule
 	regionalForwardingRule *compute.ForwardingRule
 
+	// Internal load balancer entrypoint, private DNS and Private Service Connect publishing
+	internalIPAddress *compute.Address
+	privateDNSZone    *dns.ManagedZone
+	pscNATSubnet      *compute.Subnetwork
+	serviceAttachment *compute.ServiceAttachment
+
+	// Identity Aware Proxy custom OAuth client credentials in Secret Manager
+	iapClientSecret *secretmanager.Secret
+
+	// Maintenance page bucket and the backend bucket serving it
+	maintenanceBucket        *storage.Bucket
+	maintenanceBackendBucket *compute.BackendBucket
+
+	// Custom error pages bucket and the backend bucket serving it
+	errorPagesBucket        *storage.Bucket
+	errorPagesBackendBucket *compute.BackendBucket
+
+	// IPv6 entrypoint for dual-stack clients
+	globalIPv6Address            *compute.GlobalAddress
+	globalIPv6ForwardingRule     *compute.GlobalForwardingRule
+	globalIPv6HTTPForwardingRule *compute.GlobalForwardingRule
+
+	// HTTP to HTTPS redirect entrypoint
+	httpsRedirectURLMap        *compute.URLMap
+	globalHTTPForwardingRule   *compute.GlobalForwardingRule
+	regionalHTTPForwardingRule *compute.ForwardingRule
+
 	certificate *compute.ManagedSslCertificate
-	dnsRecord   *dns.RecordSet
-	urlMap      *compute.URLMap
+	// Self-managed certificate used instead of the managed one when provided
+	sslCertificate *compute.SSLCertificate
+	sslPolicy      *compute.SSLPolicy
+	dnsRecord      *dns.RecordSet
+	dnsRecords     []*dns.RecordSet
+	urlMap         *compute.URLMap
+
+	// Cloud Armor policy of the load balancer backend services
+	cloudArmorPolicy *compute.SecurityPolicy
+	// reCAPTCHA Enterprise keys of the Cloud Armor bot management
+	recaptchaSessionTokenKey  *recaptcha.EnterpriseKey
+	recaptchaChallengePageKey *recaptcha.EnterpriseKey
+
+	// AAAA records for the IPv6 entrypoint
+	ipv6DNSRecords []*dns.RecordSet
+
+	// Certificate Manager certificate authorized via DNS
+	certificateManagerCertificate *certificatemanager.Certificate
+	certificateMap                *certificatemanager.CertificateMapResource
+	dnsAuthorizations             []*certificatemanager.DnsAuthorization
 
 	// Project-level IAM roles bound to the backend service account
 	backendProjectIamMembers []*projects.IAMMember
@@ -100,11 +174,52 @@ func NewFullStack(ctx *pulumi.Context, name string, args *FullStackArgs, opts ..
 	appBaseURL := ""
 	if args.Network != nil {
 		appBaseURL = fmt.Sprintf("https://%s", args.Network.DomainURL)
+		if args.Network.CanonicalDomain != "" {
+			appBaseURL = fmt.Sprintf("https://%s", args.Network.CanonicalDomain)
+		}
 	}
 
 	gatewayEnabled := args.Network != nil && args.Network.APIGateway != nil && !args.Network.APIGateway.Disabled
 	loadBalancerEnabled := args.Network != nil && !args.Network.EnableExternalWAF
 
+	var backendLBServiceArgs, frontendLBServiceArgs *LoadBalancerServiceArgs
+	if args.Backend != nil {
+		backendLBServiceArgs = args.Backend.LoadBalancerService
+	}
+	if args.Frontend != nil {
+		frontendLBServiceArgs = args.Frontend.LoadBalancerService
+	}
+
+	if args.Backend != nil && args.Backend.CacheInstance != nil {
+		applyCacheConfigDefaults(args.Backend.CacheInstance)
+		if err := validateCacheConfig(args.Backend.CacheInstance); err != nil {
+			return nil, fmt.Errorf("invalid cache config: %w", err)
+		}
+	}
+
+	if loadBalancerEnabled {
+		if err := validateLoadBalancerArgs(args.Network); err != nil {
+			return nil, fmt.Errorf("invalid load balancer config: %w", err)
+		}
+	}
+
+	// Validated even without a load balancer, since the request timeout is set on Cloud Run
+	applyLoadBalancerServiceDefaults(backendLBServiceArgs)
+	if err := validateLoadBalancerService(backendLBServiceArgs); err != nil {
+		return nil, fmt.Errorf("invalid backend load balancer service: %w", err)
+	}
+
+	applyLoadBalancerServiceDefaults(frontendLBServiceArgs)
+	if err := validateLoadBalancerService(frontendLBServiceArgs); err != nil {
+		return nil, fmt.Errorf("invalid frontend load balancer service: %w", err)
+	}
+
+	if args.Backend != nil && args.Backend.Canary != nil {
+		if err := validateCanary(args.Backend.Canary, args.Network); err != nil {
+			return nil, fmt.Errorf("invalid backend canary: %w", err)
+		}
+	}
+
 	fullStack := &FullStack{
 		Project:       args.Project,
 		Region:        args.Region,
@@ -120,6 +235,15 @@ func NewFullStack(ctx *pulumi.Context, name string, args *FullStackArgs, opts ..
 		gatewayEnabled:      gatewayEnabled,
 		loadBalancerEnabled: loadBalancerEnabled,
 	}
+	if loadBalancerEnabled {
+		fullStack.loadBalancingScheme = args.Network.LoadBalancingScheme
+		fullStack.externalManagedMigration = args.Network.ExternalManagedMigration
+		fullStack.backendLBServiceArgs = backendLBServiceArgs
+		fullStack.frontendLBServiceArgs = frontendLBServiceArgs
+		if args.Backend != nil {
+			fullStack.backendCanaryArgs = args.Backend.Canary
+		}
+	}
 	err := ctx.RegisterComponentResource("pulumi-fullstack:gcp:FullStack", name, fullStack, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to register component resource: %w", err)
@@ -146,6 +270,12 @@ func NewFullStack(ctx *pulumi.Context, name string, args *FullStackArgs, opts ..
 	if fullStack.frontendService != nil {
 		outputs["frontendServiceUrl"] = fullStack.frontendService.Uri
 	}
+	if fullStack.backendCanaryService != nil {
+		outputs["backendCanaryServiceUrl"] = fullStack.backendCanaryService.Uri
+	}
+	if fullStack.recaptchaSessionTokenKey != nil {
+		outputs["recaptchaSiteKey"] = fullStack.recaptchaSessionTokenKey.Name
+	}
 
 	err = ctx.RegisterResourceOutputs(fullStack, outputs)
 	if err != nil {
@@ -180,7 +310,21 @@ func (f *FullStack) deploy(ctx *pulumi.Context, args *FullStackArgs) error {
 	f.backendService = backendService
 	f.backendAccount = backendAcccount
 
-	frontendService, frontendAccount, err := f.deployFrontendCloudRunInstance(ctx, args.Frontend, backendService.Uri)
+	// The frontend reaches the backend via its public hostname when served on its own API domain
+	backendURL := backendService.Uri
+	if f.loadBalancerEnabled && args.Network.APIDomain != "" {
+		backendURL = pulumi.Sprintf("https://%s", args.Network.APIDomain)
+	}
+
+	// The frontend attaches reCAPTCHA session tokens to the requests assessed by Cloud Armor
+	if f.loadBalancerEnabled && args.Network.BotManagement != nil {
+		err = f.newRecaptchaKeys(ctx, args.Network.BotManagement)
+		if err != nil {
+			return fmt.Errorf("failed to create reCAPTCHA keys: %w", err)
+		}
+	}
+
+	frontendService, frontendAccount, err := f.deployFrontendCloudRunInstance(ctx, args.Frontend, backendURL)
 	if err != nil {
 		return fmt.Errorf("failed to deploy frontend Cloud Run: %w", err)
 	}
@@ -247,6 +391,21 @@ func (f *FullStack) GetBackendService() *cloudrunv2.Service {
 	return f.backendService
 }
 
+// GetBackendCanaryService returns the backend canary Cloud Run service. Nil unless a canary is active.
+func (f *FullStack) GetBackendCanaryService() *cloudrunv2.Service {
+	return f.backendCanaryService
+}
+
+// GetBackendCanaryNEG returns the region network endpoint group for the backend canary service.
+func (f *FullStack) GetBackendCanaryNEG() *compute.RegionNetworkEndpointGroup {
+	return f.backendCanaryNeg
+}
+
+// GetBackendCanaryLoadBalancerService returns the load balancer backend service routing to the backend canary NEG.
+func (f *FullStack) GetBackendCanaryLoadBalancerService() *compute.BackendService {
+	return f.backendCanaryLBService
+}
+
 // GetFrontendService returns the frontend Cloud Run service.
 func (f *FullStack) GetFrontendService() *cloudrunv2.Service {
 	return f.frontendService
@@ -272,21 +431,196 @@ func (f *FullStack) GetFrontendGatewayIamMember() *cloudrunv2.ServiceIamMember {
 	return f.frontendGatewayIamMember
 }
 
+// GetProxySubnet returns the proxy-only subnet of the load balancer proxies, created or existing, if any.
+func (f *FullStack) GetProxySubnet() *compute.Subnetwork {
+	return f.proxySubnet
+}
+
+// GetBackendLoadBalancerService returns the load balancer backend service routing to the backend NEG.
+func (f *FullStack) GetBackendLoadBalancerService() *compute.BackendService {
+	return f.backendLBService
+}
+
+// GetFrontendLoadBalancerService returns the load balancer backend service routing to the frontend NEG.
+func (f *FullStack) GetFrontendLoadBalancerService() *compute.BackendService {
+	return f.frontendLBService
+}
+
+// GetRegionBackendLoadBalancerService returns the regional load balancer backend service routing to the backend NEG.
+func (f *FullStack) GetRegionBackendLoadBalancerService() *compute.RegionBackendService {
+	return f.regionBackendLBService
+}
+
+// GetRegionFrontendLoadBalancerService returns the regional load balancer backend service routing to the frontend NEG.
+func (f *FullStack) GetRegionFrontendLoadBalancerService() *compute.RegionBackendService {
+	return f.regionFrontendLBService
+}
+
+// GetRegionURLMap returns the URL map of the regional load balancer.
+func (f *FullStack) GetRegionURLMap() *compute.RegionUrlMap {
+	return f.regionURLMap
+}
+
+// GetRegionSSLCertificate returns the self-managed regional SSL certificate when provided.
+func (f *FullStack) GetRegionSSLCertificate() *compute.RegionSslCertificate {
+	return f.regionSSLCertificate
+}
+
+// GetRegionSSLPolicy returns the SSL policy attached to the regional HTTPS proxy.
+func (f *FullStack) GetRegionSSLPolicy() *compute.RegionSslPolicy {
+	return f.regionSSLPolicy
+}
+
 // GetCertificate returns the managed SSL certificate for the domain.
 func (f *FullStack) GetCertificate() *compute.ManagedSslCertificate {
 	return f.certificate
 }
 
+// GetSSLCertificate returns the self-managed SSL certificate when provided.
+func (f *FullStack) GetSSLCertificate() *compute.SSLCertificate {
+	return f.sslCertificate
+}
+
+// GetCertificateSelfLink returns the self link of the certificate attached to the HTTPS proxy,
+// either the Google-managed or the self-managed one.
+func (f *FullStack) GetCertificateSelfLink() pulumi.StringOutput {
+	if f.sslCertificate != nil {
+		return f.sslCertificate.SelfLink
+	}
+
+	if f.regionSSLCertificate != nil {
+		return f.regionSSLCertificate.SelfLink
+	}
+
+	if f.certificate != nil {
+		return f.certificate.SelfLink
+	}
+
+	return pulumi.String("").ToStringOutput()
+}
+
+// GetSSLPolicy returns the SSL policy attached to the HTTPS proxy.
+func (f *FullStack) GetSSLPolicy() *compute.SSLPolicy {
+	return f.sslPolicy
+}
+
+// GetCloudArmorPolicy returns the Cloud Armor security policy of the load balancer when enabled.
+func (f *FullStack) GetCloudArmorPolicy() *compute.SecurityPolicy {
+	return f.cloudArmorPolicy
+}
+
+// GetRecaptchaSessionTokenKey returns the reCAPTCHA Enterprise key of the frontend session tokens
+// when bot management is enabled. Its name is the site key.
+func (f *FullStack) GetRecaptchaSessionTokenKey() *recaptcha.EnterpriseKey {
+	return f.recaptchaSessionTokenKey
+}
+
+// GetRecaptchaChallengePageKey returns the reCAPTCHA Enterprise key of the Cloud Armor challenge page
+// when bot management is enabled.
+func (f *FullStack) GetRecaptchaChallengePageKey() *recaptcha.EnterpriseKey {
+	return f.recaptchaChallengePageKey
+}
+
+// GetCertificateManagerCertificate returns the Certificate Manager certificate when enabled.
+func (f *FullStack) GetCertificateManagerCertificate() *certificatemanager.Certificate {
+	return f.certificateManagerCertificate
+}
+
+// GetCertificateMap returns the certificate map attached to the HTTPS proxy when Certificate Manager is enabled.
+func (f *FullStack) GetCertificateMap() *certificatemanager.CertificateMapResource {
+	return f.certificateMap
+}
+
+// GetDNSAuthorizations returns the Certificate Manager DNS authorizations for the certificate domains.
+func (f *FullStack) GetDNSAuthorizations() []*certificatemanager.DnsAuthorization {
+	return f.dnsAuthorizations
+}
+
 // GetGlobalForwardingRule returns the global forwarding rule for the load balancer.
 func (f *FullStack) GetGlobalForwardingRule() *compute.GlobalForwardingRule {
 	return f.globalForwardingRule
 }
 
-// GetRegionalForwardingRule returns the regional forwarding rule for the load balancer when regional entrypoint is enabled.
+// GetRegionalForwardingRule returns the regional forwarding rule for the load balancer when the global entrypoint is disabled.
 func (f *FullStack) GetRegionalForwardingRule() *compute.ForwardingRule {
 	return f.regionalForwardingRule
 }
 
+// GetInternalIPAddress returns the IP address of the internal load balancer when traffic is private only.
+func (f *FullStack) GetInternalIPAddress() *compute.Address {
+	return f.internalIPAddress
+}
+
+// GetPrivateDNSZone returns the private DNS zone created for the internal load balancer.
+func (f *FullStack) GetPrivateDNSZone() *dns.ManagedZone {
+	return f.privateDNSZone
+}
+
+// GetPSCNATSubnet returns the NAT subnet of the Private Service Connect service attachment.
+func (f *FullStack) GetPSCNATSubnet() *compute.Subnetwork {
+	return f.pscNATSubnet
+}
+
+// GetServiceAttachment returns the Private Service Connect service attachment publishing the internal load balancer.
+func (f *FullStack) GetServiceAttachment() *compute.ServiceAttachment {
+	return f.serviceAttachment
+}
+
+// GetIAPClientSecret returns the Secret Manager secret holding the custom IAP OAuth client credentials.
+func (f *FullStack) GetIAPClientSecret() *secretmanager.Secret {
+	return f.iapClientSecret
+}
+
+// GetMaintenanceBucket returns the Cloud Storage bucket holding the maintenance page.
+func (f *FullStack) GetMaintenanceBucket() *storage.Bucket {
+	return f.maintenanceBucket
+}
+
+// GetMaintenanceBackendBucket returns the backend bucket serving the maintenance page.
+func (f *FullStack) GetMaintenanceBackendBucket() *compute.BackendBucket {
+	return f.maintenanceBackendBucket
+}
+
+// GetErrorPagesBucket returns the Cloud Storage bucket holding the custom error pages.
+func (f *FullStack) GetErrorPagesBucket() *storage.Bucket {
+	return f.errorPagesBucket
+}
+
+// GetErrorPagesBackendBucket returns the backend bucket serving the custom error pages.
+func (f *FullStack) GetErrorPagesBackendBucket() *compute.BackendBucket {
+	return f.errorPagesBackendBucket
+}
+
+// GetGlobalHTTPForwardingRule returns the global port 80 forwarding rule redirecting to HTTPS.
+func (f *FullStack) GetGlobalHTTPForwardingRule() *compute.GlobalForwardingRule {
+	return f.globalHTTPForwardingRule
+}
+
+// GetRegionalHTTPForwardingRule returns the regional port 80 forwarding rule redirecting to HTTPS.
+func (f *FullStack) GetRegionalHTTPForwardingRule() *compute.ForwardingRule {
+	return f.regionalHTTPForwardingRule
+}
+
+// GetGlobalIPv6Address returns the global IPv6 address of the load balancer when IPv6 is enabled.
+func (f *FullStack) GetGlobalIPv6Address() *compute.GlobalAddress {
+	return f.globalIPv6Address
+}
+
+// GetGlobalIPv6ForwardingRule returns the global IPv6 HTTPS forwarding rule when IPv6 is enabled.
+func (f *FullStack) GetGlobalIPv6ForwardingRule() *compute.GlobalForwardingRule {
+	return f.globalIPv6ForwardingRule
+}
+
+// GetGlobalIPv6HTTPForwardingRule returns the global IPv6 port 80 forwarding rule redirecting to HTTPS.
+func (f *FullStack) GetGlobalIPv6HTTPForwardingRule() *compute.GlobalForwardingRule {
+	return f.globalIPv6HTTPForwardingRule
+}
+
+// GetHTTPSRedirectURLMap returns the redirect-only URL map for plain HTTP traffic.
+func (f *FullStack) GetHTTPSRedirectURLMap() *compute.URLMap {
+	return f.httpsRedirectURLMap
+}
+
 // LookupDNSZone finds the appropriate DNS managed zone for the given domain in the current project
 func (f *FullStack) LookupDNSZone(ctx *pulumi.Context, domainURL string) (string, error) {
 	return f.lookupDNSZone(ctx, domainURL)
@@ -297,6 +631,16 @@ func (f *FullStack) GetDNSRecord() *dns.RecordSet {
 	return f.dnsRecord
 }
 
+// GetDNSRecords returns the DNS records created for all the load balancer domains
+func (f *FullStack) GetDNSRecords() []*dns.RecordSet {
+	return f.dnsRecords
+}
+
+// GetIPv6DNSRecords returns the AAAA records created for all the load balancer domains when IPv6 is enabled
+func (f *FullStack) GetIPv6DNSRecords() []*dns.RecordSet {
+	return f.ipv6DNSRecords
+}
+
 // GetBackendAccount returns the backend service account.
 func (f *FullStack) GetBackendAccount() *serviceaccount.Account {
 	return f.backendAccount
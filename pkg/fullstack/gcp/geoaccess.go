@@ -0,0 +1,90 @@
+package gcp
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	compute "github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/compute"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+const (
+	geoAccessModeAllow = "allow"
+	geoAccessModeDeny  = "deny"
+
+	defaultGeoAccessDenyStatusCode = 403
+
+	// The geo access rule is evaluated first, so allowlisted client IPs are also subject to it
+	geoAccessRulePriority = 0
+)
+
+var (
+	// ISO 3166-1 alpha-2 codes, as resolved by Cloud Armor in origin.region_code
+	regionCodePattern     = regexp.MustCompile("^[A-Z]{2}$")
+	geoAccessDenyStatuses = []int{403, 404, 502}
+)
+
+func applyGeoAccessDefaults(args *GeoAccessArgs) {
+	if args.DenyStatusCode == 0 {
+		args.DenyStatusCode = defaultGeoAccessDenyStatusCode
+	}
+}
+
+func validateGeoAccess(args *NetworkArgs) error {
+	geoAccess := args.GeoAccess
+
+	if !args.EnableCloudArmor {
+		return fmt.Errorf("geo access requires Cloud Armor to be enabled")
+	}
+
+	if geoAccess.Mode != geoAccessModeAllow && geoAccess.Mode != geoAccessModeDeny {
+		return fmt.Errorf("mode must be %q or %q, got %q", geoAccessModeAllow, geoAccessModeDeny, geoAccess.Mode)
+	}
+
+	if len(geoAccess.RegionCodes) == 0 {
+		return fmt.Errorf("at least one region code is required")
+	}
+
+	for _, regionCode := range geoAccess.RegionCodes {
+		if !regionCodePattern.MatchString(regionCode) {
+			return fmt.Errorf("region code %q must be an uppercase ISO 3166-1 alpha-2 code, e.g. \"DE\"", regionCode)
+		}
+	}
+
+	if !slices.Contains(geoAccessDenyStatuses, geoAccess.DenyStatusCode) {
+		return fmt.Errorf("deny status code must be one of %v, got %d", geoAccessDenyStatuses, geoAccess.DenyStatusCode)
+	}
+
+	return nil
+}
+
+// newGeoAccessRule creates the rule denying the requests from the regions of the deny list, or
+// from the regions outside of the allow list. It's evaluated before the client IP allowlist, so
+// a client must pass both.
+//
+// See:
+// https://cloud.google.com/armor/docs/rules-language-reference#attributes
+func newGeoAccessRule(args *GeoAccessArgs) *compute.SecurityPolicyRuleTypeArgs {
+	// A single regex keeps the rule within the subexpressions limit of Cloud Armor
+	regionMatch := fmt.Sprintf("origin.region_code.matches('^(%s)$')", strings.Join(args.RegionCodes, "|"))
+
+	expression := regionMatch
+	description := fmt.Sprintf("Deny requests from %s", strings.Join(args.RegionCodes, ", "))
+	if args.Mode == geoAccessModeAllow {
+		expression = "!" + regionMatch
+		description = fmt.Sprintf("Deny requests from outside of %s", strings.Join(args.RegionCodes, ", "))
+	}
+
+	return &compute.SecurityPolicyRuleTypeArgs{
+		Action:      pulumi.String(fmt.Sprintf("deny(%d)", args.DenyStatusCode)),
+		Description: pulumi.String(description),
+		Priority:    pulumi.Int(geoAccessRulePriority),
+		Match: &compute.SecurityPolicyRuleMatchArgs{
+			Expr: &compute.SecurityPolicyRuleMatchExprArgs{
+				Expression: pulumi.String(expression),
+			},
+		},
+	}
+}
@@ -0,0 +1,178 @@
+package gcp
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	compute "github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/compute"
+)
+
+// Max custom request and response headers of a backend service
+const maxCustomHeaders = 16
+
+var (
+	// Hardened preset of security response headers
+	securityResponseHeaders = map[string]string{
+		"Strict-Transport-Security": "max-age=31536000; includeSubDomains",
+		"Content-Security-Policy":   "frame-ancestors 'none'; object-src 'none'; base-uri 'self'",
+		"X-Content-Type-Options":    "nosniff",
+		"X-Frame-Options":           "DENY",
+		"Referrer-Policy":           "strict-origin-when-cross-origin",
+	}
+	// Client details forwarded to the upstreams, resolved by the load balancer per request
+	clientRequestHeaders = map[string]string{
+		"X-Client-Region":   "{client_region}",
+		"X-Client-City":     "{client_city}",
+		"X-Client-RTT-Msec": "{client_rtt_msec}",
+		"X-TLS-Version":     "{tls_version}",
+	}
+
+	headerNamePattern = regexp.MustCompile("^[A-Za-z0-9!#$%&'*+.^_`|~-]+$")
+	// Headers the load balancer manages and won't let a backend service set
+	reservedHeaders = []string{
+		"host", "authority", "connection", "keep-alive", "te", "trailer", "transfer-encoding", "upgrade",
+		"via", "content-length", "content-encoding", "proxy-authenticate", "proxy-authorization", "x-user-ip",
+	}
+	reservedHeaderPrefixes = []string{"x-google", "x-goog-", "x-gfe", "x-amz-"}
+)
+
+func applyHeadersDefaults(args *HeadersArgs) *HeadersArgs {
+	if args == nil {
+		args = &HeadersArgs{}
+	}
+
+	return args
+}
+
+func validateHeaders(args *HeadersArgs) error {
+	for _, upstream := range []string{UpstreamBackend, UpstreamFrontend} {
+		requestHeaders, responseHeaders := resolveUpstreamHeaders(args, upstream)
+
+		if err := validateHeaderList(requestHeaders); err != nil {
+			return fmt.Errorf("invalid %s request headers: %w", upstream, err)
+		}
+
+		if err := validateHeaderList(responseHeaders); err != nil {
+			return fmt.Errorf("invalid %s response headers: %w", upstream, err)
+		}
+	}
+
+	return nil
+}
+
+func validateHeaderList(headers []string) error {
+	if len(headers) > maxCustomHeaders {
+		return fmt.Errorf("at most %d headers are supported, got %d", maxCustomHeaders, len(headers))
+	}
+
+	for _, header := range headers {
+		name, value, _ := strings.Cut(header, ": ")
+
+		if !headerNamePattern.MatchString(name) {
+			return fmt.Errorf("header name %q is not valid", name)
+		}
+
+		lowerName := strings.ToLower(name)
+		if slices.Contains(reservedHeaders, lowerName) {
+			return fmt.Errorf("header %s is reserved by the load balancer", name)
+		}
+		for _, prefix := range reservedHeaderPrefixes {
+			if strings.HasPrefix(lowerName, prefix) {
+				return fmt.Errorf("header %s is reserved by the load balancer", name)
+			}
+		}
+
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("value of header %s can't contain line breaks", name)
+		}
+	}
+
+	return nil
+}
+
+// resolveUpstreamHeaders merges the preset, the shared and the upstream headers, in order of
+// precedence, into the "Name: value" format of the backend service. Names are case-insensitive,
+// and an empty value removes a header set by a preceding layer.
+func resolveUpstreamHeaders(args *HeadersArgs, upstream string) ([]string, []string) {
+	requestLayers := []map[string]string{}
+	responseLayers := []map[string]string{}
+
+	if !args.DisablePreset {
+		requestLayers = append(requestLayers, clientRequestHeaders)
+		responseLayers = append(responseLayers, securityResponseHeaders)
+	}
+
+	requestLayers = append(requestLayers, args.RequestHeaders)
+	responseLayers = append(responseLayers, args.ResponseHeaders)
+
+	overrides := args.Backend
+	if upstream == UpstreamFrontend {
+		overrides = args.Frontend
+	}
+	if overrides != nil {
+		requestLayers = append(requestLayers, overrides.RequestHeaders)
+		responseLayers = append(responseLayers, overrides.ResponseHeaders)
+	}
+
+	return mergeHeaders(requestLayers...), mergeHeaders(responseLayers...)
+}
+
+func mergeHeaders(layers ...map[string]string) []string {
+	merged := map[string]string{}
+	names := map[string]string{}
+
+	for _, layer := range layers {
+		// Sort each layer so names differing only in case resolve deterministically
+		layerNames := make([]string, 0, len(layer))
+		for name := range layer {
+			layerNames = append(layerNames, name)
+		}
+		slices.Sort(layerNames)
+
+		for _, name := range layerNames {
+			key := strings.ToLower(name)
+			if layer[name] == "" {
+				delete(merged, key)
+				delete(names, key)
+
+				continue
+			}
+			merged[key] = layer[name]
+			names[key] = name
+		}
+	}
+
+	keys := make([]string, 0, len(merged))
+	for key := range merged {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	headers := make([]string, 0, len(keys))
+	for _, key := range keys {
+		headers = append(headers, fmt.Sprintf("%s: %s", names[key], merged[key]))
+	}
+
+	return headers
+}
+
+// applyHeaders sets the custom request and response headers of an upstream on the backend
+// service serving it. Response headers are added to the responses from the upstream.
+//
+// See:
+// https://cloud.google.com/load-balancing/docs/https/custom-headers-global
+func applyHeaders(serviceArgs *compute.BackendServiceArgs, args *HeadersArgs, upstream string) {
+	if args == nil {
+		return
+	}
+
+	requestHeaders, responseHeaders := resolveUpstreamHeaders(args, upstream)
+	if len(requestHeaders) > 0 {
+		serviceArgs.CustomRequestHeaders = toStringArray(requestHeaders)
+	}
+	if len(responseHeaders) > 0 {
+		serviceArgs.CustomResponseHeaders = toStringArray(responseHeaders)
+	}
+}
@@ -0,0 +1,229 @@
+package gcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	compute "github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/compute"
+	"github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/iap"
+	"github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/projects"
+	secretmanager "github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/secretmanager"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// Role granting access to the upstreams protected by IAP
+const iapAccessorRole = "roles/iap.httpsResourceAccessor"
+
+var iapMemberPrefixes = []string{"user:", "group:", "serviceAccount:", "domain:"}
+
+func applyIAPDefaults(args *IAPArgs) *IAPArgs {
+	if args == nil {
+		args = &IAPArgs{}
+	}
+
+	if len(args.Upstreams) == 0 {
+		args.Upstreams = []string{UpstreamBackend, UpstreamFrontend}
+	}
+
+	return args
+}
+
+func validateIAP(args *NetworkArgs) error {
+	if !args.EnableIAP {
+		if args.IAP != nil {
+			return fmt.Errorf("IAP settings require IAP to be enabled")
+		}
+
+		return nil
+	}
+
+	if args.IAP.OAuthClientID == "" && args.IAP.OAuthClientSecret != nil {
+		return fmt.Errorf("OAuth client secret requires an OAuth client ID")
+	}
+
+	if args.IAP.OAuthClientID != "" && args.IAP.OAuthClientSecret == nil {
+		return fmt.Errorf("OAuth client ID requires an OAuth client secret")
+	}
+
+	seenUpstreams := map[string]bool{}
+	for _, upstream := range args.IAP.Upstreams {
+		if err := validateUpstream(upstream); err != nil {
+			return err
+		}
+		if seenUpstreams[upstream] {
+			return fmt.Errorf("upstream %s is declared more than once", upstream)
+		}
+		seenUpstreams[upstream] = true
+	}
+
+	// The gateway fronts both upstreams with a single backend service
+	if args.APIGateway != nil && !args.APIGateway.Disabled && len(seenUpstreams) != 2 {
+		return fmt.Errorf("API Gateway requires IAP to protect both upstreams")
+	}
+
+	if args.FrontendCDN != nil && isProtectedByIAP(args, UpstreamFrontend) {
+		return fmt.Errorf("IAP can't protect the frontend with the frontend CDN enabled")
+	}
+
+	seenMembers := map[string]bool{}
+	for _, member := range args.IAP.Members {
+		if !slices.ContainsFunc(iapMemberPrefixes, func(prefix string) bool {
+			return strings.HasPrefix(member, prefix) && len(member) > len(prefix)
+		}) {
+			return fmt.Errorf("member %q must start with one of %v", member, iapMemberPrefixes)
+		}
+		if seenMembers[member] {
+			return fmt.Errorf("member %q is declared more than once", member)
+		}
+		seenMembers[member] = true
+	}
+
+	return nil
+}
+
+// isProtectedByIAP returns true if IAP authenticates the traffic to the upstream.
+func isProtectedByIAP(args *NetworkArgs, upstream string) bool {
+	return args.EnableIAP && slices.Contains(args.IAP.Upstreams, upstream)
+}
+
+// enableIAP enables the IAP API. The protected backend services authenticate users with the
+// Google-managed OAuth client, unless a custom one is set. Its credentials are then kept in
+// Secret Manager.
+//
+// The Google-managed client only lets users of the organization sign in. Users outside of it
+// require a custom client, created in the console, since the IAP OAuth Admin API creating
+// brands and clients is deprecated.
+//
+// See:
+// https://cloud.google.com/iap/docs/enabling-cloud-run
+// https://cloud.google.com/iap/docs/custom-oauth-configuration
+// https://cloud.google.com/iap/docs/deprecations/migrate-oauth-client
+func (f *FullStack) enableIAP(ctx *pulumi.Context, serviceName string, args *NetworkArgs) error {
+	iapServiceName := f.NewResourceName(serviceName, "iap", 63)
+	_, err := projects.NewService(ctx, iapServiceName, &projects.ServiceArgs{
+		Project: pulumi.String(f.Project),
+		Service: pulumi.String("iap.googleapis.com"),
+	},
+		pulumi.RetainOnDelete(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enable IAP API: %w", err)
+	}
+
+	if args.IAP.OAuthClientID == "" {
+		return nil
+	}
+
+	secretID := f.NewResourceName(serviceName, "iap-client-secret", 63)
+	clientSecret, err := secretmanager.NewSecret(ctx, secretID, &secretmanager.SecretArgs{
+		Project: pulumi.String(f.Project),
+		Labels: mergeLabels(f.Labels, pulumi.StringMap{
+			"load_balancer": pulumi.String("true"),
+		}),
+		Replication: &secretmanager.SecretReplicationArgs{
+			// With google-managed default encryption
+			Auto: &secretmanager.SecretReplicationAutoArgs{},
+		},
+		SecretId: pulumi.String(secretID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create IAP OAuth client secret: %w", err)
+	}
+	f.iapClientSecret = clientSecret
+
+	credentials := args.IAP.OAuthClientSecret.ToStringOutput().ApplyT(func(secret string) (string, error) {
+		data, err := json.Marshal(map[string]string{
+			"client_id":     args.IAP.OAuthClientID,
+			"client_secret": secret,
+		})
+
+		return string(data), err
+	}).(pulumi.StringOutput)
+
+	_, err = secretmanager.NewSecretVersion(ctx, fmt.Sprintf("%s-version", secretID), &secretmanager.SecretVersionArgs{
+		Secret:     clientSecret.ID(),
+		SecretData: pulumi.ToSecret(credentials).(pulumi.StringOutput),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create IAP OAuth client secret version: %w", err)
+	}
+
+	return nil
+}
+
+// applyIAP enables IAP on the backend service of a protected upstream.
+func (f *FullStack) applyIAP(serviceArgs *compute.BackendServiceArgs, args *NetworkArgs, upstream string) {
+	if !isProtectedByIAP(args, upstream) {
+		return
+	}
+
+	iapArgs := &compute.BackendServiceIapArgs{
+		Enabled: pulumi.Bool(true),
+	}
+	if args.IAP.OAuthClientID != "" {
+		iapArgs.Oauth2ClientId = pulumi.String(args.IAP.OAuthClientID)
+		iapArgs.Oauth2ClientSecret = pulumi.ToSecret(args.IAP.OAuthClientSecret.ToStringOutput()).(pulumi.StringOutput)
+	}
+	serviceArgs.Iap = iapArgs
+}
+
+// iapAccessorName returns the name of the IAM member granting IAP access to a member. It's keyed
+// by the member so that removing a member doesn't replace the grants of the following ones.
+func (f *FullStack) iapAccessorName(serviceName, upstream, member string) string {
+	return f.NewResourceName(serviceName, fmt.Sprintf("%s-iap-accessor-%s", upstream, resourceKey(member)), 63)
+}
+
+// grantIAPAccess grants the IAP members access to the backend service of a protected upstream.
+func (f *FullStack) grantIAPAccess(ctx *pulumi.Context,
+	serviceName string,
+	args *NetworkArgs,
+	upstream string,
+	backendService *compute.BackendService) error {
+	if !isProtectedByIAP(args, upstream) {
+		return nil
+	}
+
+	for _, member := range args.IAP.Members {
+		accessorName := f.iapAccessorName(serviceName, upstream, member)
+		_, err := iap.NewWebBackendServiceIamMember(ctx, accessorName, &iap.WebBackendServiceIamMemberArgs{
+			Project:           pulumi.String(f.Project),
+			WebBackendService: backendService.Name,
+			Role:              pulumi.String(iapAccessorRole),
+			Member:            pulumi.String(member),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to grant IAP access to %s: %w", member, err)
+		}
+	}
+
+	return nil
+}
+
+// grantRegionIAPAccess is the regional backend service counterpart of grantIAPAccess.
+func (f *FullStack) grantRegionIAPAccess(ctx *pulumi.Context,
+	serviceName string,
+	args *NetworkArgs,
+	upstream string,
+	backendService *compute.RegionBackendService) error {
+	if !isProtectedByIAP(args, upstream) {
+		return nil
+	}
+
+	for _, member := range args.IAP.Members {
+		accessorName := f.iapAccessorName(serviceName, upstream, member)
+		_, err := iap.NewWebRegionBackendServiceIamMember(ctx, accessorName, &iap.WebRegionBackendServiceIamMemberArgs{
+			Project:                 pulumi.String(f.Project),
+			Region:                  pulumi.String(f.Region),
+			WebRegionBackendService: backendService.Name,
+			Role:                    pulumi.String(iapAccessorRole),
+			Member:                  pulumi.String(member),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to grant IAP access to %s: %w", member, err)
+		}
+	}
+
+	return nil
+}
@@ -26,11 +26,56 @@ type BackendArgs struct {
 	ProjectIAMRoles []string
 	CacheInstance   *CacheInstanceArgs
 	BucketInstance  *BucketInstanceArgs
+	// Load balancer settings for the backend. With API Gateway, they apply to the gateway backend service.
+	LoadBalancerService *LoadBalancerServiceArgs
+	// Canary release of a new backend image next to the stable one. Requires the "EXTERNAL_MANAGED"
+	// scheme and the global entrypoint, and API Gateway to be disabled. Disabled if nil.
+	Canary *CanaryArgs
+}
+
+// CanaryArgs contains the canary release of the backend. The canary is deployed as a second
+// Cloud Run service with the same settings as the backend, behind a serverless NEG of its own.
+type CanaryArgs struct {
+	// Image of the canary backend. Required.
+	Image pulumi.StringInput
+	// Percentage of the backend traffic sent to the canary, between 0 and 100. Defaults to 0.
+	TrafficPercent int
+	// Header sending matching requests to the canary regardless of TrafficPercent.
+	// E.g.: {Name: "X-Canary", Exact: "true"}. Optional.
+	Header *HeaderMatchArgs
+	// Whether to promote the canary: the backend is rolled out with Image, and the canary
+	// service, NEG and routes are removed. Set BackendImage to Image and remove the canary
+	// afterwards. Defaults to false.
+	Promote bool
 }
 
 // FrontendArgs contains configuration for the frontend service.
 type FrontendArgs struct {
 	*InstanceArgs
+	// Load balancer settings for the frontend. Not used with API Gateway.
+	LoadBalancerService *LoadBalancerServiceArgs
+}
+
+// LoadBalancerServiceArgs contains the load balancer backend service settings of an upstream.
+type LoadBalancerServiceArgs struct {
+	// Request timeout in seconds, up to 3600. Set as the Cloud Run request timeout, since serverless
+	// NEG backend services defer to it. Defaults to the Cloud Run default of 300.
+	TimeoutSeconds int
+	// Request logging of the backend service. Disabled if nil.
+	Logging *RequestLoggingArgs
+	// Seconds to let in-flight requests complete when a backend is removed, up to 3600. Defaults to GCP's 0.
+	ConnectionDrainingTimeoutSeconds int
+}
+
+// RequestLoggingArgs contains configuration for the load balancer request logs.
+type RequestLoggingArgs struct {
+	// Fraction of requests to log, between 0 and 1. Defaults to 1 if nil.
+	SampleRate *float64
+	// Optional fields to log: "EXCLUDE_ALL_OPTIONAL", "INCLUDE_ALL_OPTIONAL" or "CUSTOM".
+	// Defaults to "EXCLUDE_ALL_OPTIONAL", or "CUSTOM" if OptionalFields is set.
+	OptionalMode string
+	// Optional fields to log with the "CUSTOM" mode. E.g.: "tls.protocol", "orca_load_report".
+	OptionalFields []string
 }
 
 // Probe contains configuration for TCP and HTTP health check probes
@@ -104,25 +149,517 @@ type NetworkArgs struct {
 	// Domain name for the internet-facing certificate. Required.
 	// E.g.: "myapp.path2prod.dev"
 	DomainURL string
+	// Additional domain names served by the load balancer. They are added to the
+	// certificate, the URL map host rules and DNS. E.g.: "www.myapp.path2prod.dev"
+	AdditionalDomains []string
+	// Domain to serve the backend on its own hostname in load balancer mode. E.g.: "api.myapp.path2prod.dev".
+	// All its traffic is routed to the backend, and it's added to the certificate and DNS.
+	// The frontend gets it as BACKEND_API_URL. Not supported with API Gateway.
+	APIDomain string
+	// Domain to redirect all the other domains to. E.g.: the apex domain to redirect "www" to it.
+	// Must be DomainURL or one of AdditionalDomains. Optional.
+	CanonicalDomain string
 	// GCP network where to host the load balancer instances. Defaults to "default".
 	ProxyNetworkName string
+	// Project hosting ProxyNetworkName, e.g. a Shared VPC host project. The proxy-only subnet and
+	// the Private Service Connect NAT subnet are created there. Defaults to the stack project.
+	NetworkProject string
+	// Proxy-only subnet for the load balancer proxies in ProxyNetworkName. Only used by the
+	// regional external and internal load balancers; skipped for the classic and global load balancers.
+	ProxySubnet *ProxySubnetArgs
 	// Whether to apply best-practice Cloud Armor policies to the load balancer. Defaults to false.
 	EnableCloudArmor bool
 	// Whether to enable Identity Aware Proxy for authentication. Defaults to false.
 	EnableIAP bool
-	// Support email for IAP's OAuth consent screen. Required if EnableIAP=true.
-	IAPSupportEmail string
-	// Whether to restrict access to the given list of client IPs. Valid only when EnableCloudArmor=true.
+	// Identity Aware Proxy settings. Only used with EnableIAP=true.
+	IAP *IAPArgs
+	// Whether to restrict access to the given list of client IPs or CIDR ranges. Up to 5 together
+	// with ClientIPAllowlistNamedLists. Valid only when EnableCloudArmor=true.
 	ClientIPAllowlist []string
-	// Whether to disable public internet access. Useful during development. Defaults to false.
+	// Cloud Armor named IP lists of the clients allowed next to ClientIPAllowlist, e.g. the edge of
+	// the CDN or WAF in front of the load balancer. E.g.: "sourceiplist-cloudflare". Valid only when
+	// EnableCloudArmor=true.
+	ClientIPAllowlistNamedLists []string
+	// Adaptive Protection layer 7 DDoS defense of the Cloud Armor policy. Valid only when EnableCloudArmor=true.
+	AdaptiveProtection *AdaptiveProtectionArgs
+	// Cloud Armor rate limits, e.g. to throttle or ban credential stuffing clients on a login
+	// path. Evaluated in order after the preconfigured WAF rules. Valid only when EnableCloudArmor=true.
+	RateLimits []*RateLimitArgs
+	// reCAPTCHA Enterprise bot management of the Cloud Armor policy. The frontend gets the session
+	// token site key as RECAPTCHA_SITE_KEY. Valid only when EnableCloudArmor=true.
+	BotManagement *BotManagementArgs
+	// Allow or deny list of client regions, evaluated before ClientIPAllowlist so a client must
+	// pass both. Valid only when EnableCloudArmor=true.
+	GeoAccess *GeoAccessArgs
+	// Custom Cloud Armor rules merged with the generated ones. Priorities must be unique, and
+	// 1-2, 15, 20-29, 50-59, 100-109 and 2147483647 are reserved, as well as 0 with GeoAccess.
+	// Valid only when EnableCloudArmor=true.
+	CloudArmorRules []*CloudArmorRuleArgs
+	// Whether to disable public internet access. The load balancer is deployed as a regional internal
+	// Application Load Balancer in ProxyNetworkName, reachable from the VPC and networks connected to it,
+	// e.g. on-prem over Cloud Interconnect. Requires the "INTERNAL_MANAGED" scheme. Defaults to false.
 	EnablePrivateTrafficOnly bool
+	// Internal load balancer settings. Only used with EnablePrivateTrafficOnly=true.
+	InternalLoadBalancer *InternalLoadBalancerArgs
 	// API Gateway configuration. If provided, traffic will be routed through API Gateway.
 	APIGateway *APIGatewayArgs
 	// Whether to enable global forwarding rule and global IP address for the load balancer.
 	EnableGlobalEntrypoint bool
+	// Whether to reserve an IPv6 address and publish AAAA records next to the IPv4 ones.
+	// Requires EnableGlobalEntrypoint=true. Defaults to false.
+	EnableIPv6 bool
+	// Load balancing scheme: "EXTERNAL" for the classic Application Load Balancer, "EXTERNAL_MANAGED"
+	// for the Envoy-based one with advanced traffic management or "INTERNAL_MANAGED" for the internal one.
+	// Defaults to "EXTERNAL", or "INTERNAL_MANAGED" with EnablePrivateTrafficOnly=true.
+	// "EXTERNAL_MANAGED" with EnableGlobalEntrypoint=false deploys a regional external Application
+	// Load Balancer, terminating TLS in the stack region.
+	LoadBalancingScheme string
+	// Migration state of the backend services to move from "EXTERNAL" to "EXTERNAL_MANAGED"
+	// keeping the reserved IP. Remove once the migration is complete. Optional.
+	ExternalManagedMigration *ExternalManagedMigrationArgs
 	// Whether to secure the frontend and backend instances with an external WAF using Cloud Run Domain Mapping.
 	// Set to true to disable the external load balancer. Defaults to false.
 	EnableExternalWAF bool
+	// Plain HTTP entrypoint on port 80 redirecting to HTTPS. Enabled by default.
+	HTTPSRedirect *HTTPSRedirectArgs
+	// Load balancer URL map routing to the Cloud Run instances.
+	// Defaults to "/api/*" to the backend and "/*" to the frontend.
+	Routing *RoutingArgs
+	// Certificate Manager certificate authorized via DNS. When set, it replaces the classic
+	// Google-managed certificate so TLS is ready before DNS points at the load balancer.
+	CertificateManager *CertificateManagerArgs
+	// Self-managed TLS certificate, e.g. for EV or OV certificates. When set, it replaces
+	// the classic Google-managed certificate.
+	TLSCertificate *TLSCertificateArgs
+	// SSL policy of the HTTPS proxy. Defaults to the MODERN profile with TLS 1.2 as the minimum version.
+	SSLPolicy *SSLPolicyArgs
+	// Cloud CDN for the frontend. Only the frontend backend service is cached, never the backend API.
+	// Requires API Gateway to be disabled. Disabled if nil.
+	FrontendCDN *CDNArgs
+	// Security response headers and custom request headers set by the load balancer on both upstreams.
+	// Defaults to a hardened preset of security headers, and the client geo, TLS version and RTT
+	// forwarded to the upstreams. Not supported by the regional load balancers.
+	Headers *HeadersArgs
+	// Maintenance page served from a bucket instead of the upstreams while enabled. The bucket
+	// is kept when disabled so the switch only updates the URL map. Not supported by the
+	// regional load balancers. Optional.
+	MaintenanceMode *MaintenanceModeArgs
+	// Custom error pages served from a bucket instead of the raw load balancer and upstream error
+	// responses. Requires the "EXTERNAL_MANAGED" scheme and the global entrypoint. Optional.
+	ErrorPages *ErrorPagesArgs
+}
+
+// ErrorPagesArgs contains the custom error pages of the load balancer responses.
+type ErrorPagesArgs struct {
+	// Local directory of the Pulumi program with the error pages. All its files are uploaded
+	// to the error pages bucket, keeping their relative paths. Required.
+	Directory string
+	// Pages of the backend error responses. With API Gateway, they apply to the gateway.
+	Backend []*ErrorPageArgs
+	// Pages of the frontend error responses. Not used with API Gateway.
+	Frontend []*ErrorPageArgs
+}
+
+// ErrorPageArgs maps error status codes of an upstream to a page.
+type ErrorPageArgs struct {
+	// Status codes between 400 and 599, or the "4xx" and "5xx" ranges. Single codes take
+	// precedence over ranges. E.g.: ["502", "503"]. Required.
+	StatusCodes []string
+	// Path of the page in Directory. E.g.: "/errors/5xx.html". Required.
+	Page string
+	// Status code returned with the page. Defaults to the status code of the upstream.
+	OverrideStatusCode int
+}
+
+// AdaptiveProtectionArgs contains the Adaptive Protection settings of Cloud Armor.
+type AdaptiveProtectionArgs struct {
+	// Visibility of the suggested rules: "STANDARD", or "PREMIUM" with Cloud Armor Enterprise.
+	// Defaults to "STANDARD".
+	RuleVisibility string
+	// Whether to automatically deploy the suggested rules during an attack. Requires Cloud Armor
+	// Enterprise. Disabled if nil.
+	AutoDeploy *AdaptiveProtectionAutoDeployArgs
+}
+
+// AdaptiveProtectionAutoDeployArgs contains the thresholds of the rules auto-deployed by Adaptive Protection.
+type AdaptiveProtectionAutoDeployArgs struct {
+	// Load of the backend services, between 0 and 1, above which rules are deployed. Defaults to 0.8.
+	LoadThreshold float64
+	// Confidence of the attack detection, between 0 and 1, above which rules are deployed. Defaults to 0.5.
+	ConfidenceThreshold float64
+	// Share of the baseline traffic, between 0 and 1, the rules may block at most. Defaults to 0.01.
+	ImpactedBaselineThreshold float64
+	// Seconds the deployed rules are enforced for. Defaults to 7200.
+	ExpirationSeconds int
+	// Status code of the requests denied by the deployed rules, one of 403, 404 or 502. Defaults to 403.
+	DenyStatusCode int
+}
+
+// GeoAccessArgs contains the client regions allowed or denied by Cloud Armor.
+type GeoAccessArgs struct {
+	// "allow" to deny the requests from the regions outside of RegionCodes, or "deny" to deny
+	// the requests from RegionCodes. Required.
+	Mode string
+	// Uppercase ISO 3166-1 alpha-2 region codes. E.g.: ["CU", "IR", "KP", "SY"]. Required.
+	RegionCodes []string
+	// Status code of the denied requests, one of 403, 404 or 502. Defaults to 403.
+	DenyStatusCode int
+}
+
+// CloudArmorRuleArgs contains a custom rule of the Cloud Armor policy.
+type CloudArmorRuleArgs struct {
+	// Description of the rule. Optional.
+	Description string
+	// Priority between 3 and 2147483646, lower priorities being evaluated first. Must be unique,
+	// and 15, 20-29, 50-59 and 100-109 are reserved for the generated rules. Required.
+	Priority int
+	// "allow", "deny(403)", "deny(404)", "deny(502)" or "redirect". Required.
+	Action string
+	// Cloud Armor CEL expression matching the requests. E.g.: "origin.region_code == 'XX'".
+	// Required without SourceIPRanges.
+	Expression string
+	// Up to 10 client IPs or CIDR ranges matched by the rule. Required without Expression.
+	SourceIPRanges []string
+	// Whether to only log the matches without enforcing the action. Defaults to false.
+	Preview bool
+	// Redirect of the matched requests. Required with the "redirect" action only.
+	Redirect *CloudArmorRedirectArgs
+}
+
+// CloudArmorRedirectArgs contains the redirect of a custom Cloud Armor rule.
+type CloudArmorRedirectArgs struct {
+	// "EXTERNAL_302" to redirect to Target, or "GOOGLE_RECAPTCHA" to the reCAPTCHA challenge page. Required.
+	Type string
+	// URL to redirect to with "EXTERNAL_302". E.g.: "https://myapp.example.com/blocked".
+	Target string
+}
+
+// BotManagementArgs contains the reCAPTCHA Enterprise bot management settings of Cloud Armor.
+type BotManagementArgs struct {
+	// Path prefixes where the clients are assessed. E.g.: "/api/auth/login". Required.
+	Paths []string
+	// reCAPTCHA score between 0 and 1 below which clients are challenged or denied. Clients
+	// without a valid session token are too. Defaults to 0.5.
+	ScoreThreshold float64
+	// "redirect" to the reCAPTCHA challenge page, or "deny" with a 403. Defaults to "redirect".
+	Action string
+	// Domains allowed to use the reCAPTCHA keys. Defaults to the load balancer domains.
+	AllowedDomains []string
+}
+
+// RateLimitArgs contains a Cloud Armor rate limit of the clients of the load balancer.
+type RateLimitArgs struct {
+	// Description of the Cloud Armor rule. Optional.
+	Description string
+	// "throttle" to deny the requests over the threshold, or "rate_based_ban" to also deny all
+	// the requests of the client for BanDurationSeconds. Defaults to "throttle".
+	Action string
+	// Requests allowed per client in each interval. Required.
+	ThresholdCount int
+	// Interval of the threshold, one of 10, 30, 60, 120, 180, 240, 300, 600, 900, 1200, 1800,
+	// 2700 or 3600 seconds. Defaults to 60.
+	IntervalSeconds int
+	// Seconds a client is banned for with "rate_based_ban". Defaults to 600.
+	BanDurationSeconds int
+	// Key identifying the clients: "IP", "XFF_IP", "HTTP_HEADER", "HTTP_COOKIE" or "ALL".
+	// Defaults to "IP".
+	EnforceOnKey string
+	// Name of the header or cookie identifying the clients. Required with "HTTP_HEADER" and
+	// "HTTP_COOKIE" only.
+	EnforceOnKeyName string
+	// Status code of the denied requests, one of 403, 404, 429 or 502. Defaults to 429.
+	ExceedStatusCode int
+	// Path prefix the rate limit applies to. E.g.: "/api/auth/login". Defaults to all paths.
+	Path string
+	// Cloud Armor CEL expression the rate limit applies to, in place of Path.
+	// E.g.: "request.method == 'POST' && request.path == '/api/auth/login'". Optional.
+	Expression string
+}
+
+// MaintenanceModeArgs contains the maintenance mode settings of the load balancer.
+type MaintenanceModeArgs struct {
+	// Whether to serve the maintenance page instead of the upstreams. Defaults to false.
+	Enabled bool
+	// Full path still routed to the backend in maintenance mode, e.g. for uptime checks.
+	// With API Gateway, it's routed to the gateway. Defaults to "/healthz".
+	HealthPath string
+	// Client IPs or CIDR ranges still reaching the upstreams in maintenance mode, e.g. to check
+	// a migration before reopening. The other clients are redirected to the maintenance page by
+	// Cloud Armor, so it requires EnableCloudArmor. At most 4. Optional.
+	ClientIPAllowlist []string
+	// HTML of the maintenance page served for every other path. Defaults to a generic page.
+	PageHTML string
+}
+
+// IAPArgs contains the Identity Aware Proxy settings of the load balancer.
+type IAPArgs struct {
+	// Users and groups granted "roles/iap.httpsResourceAccessor" on the protected upstreams.
+	// E.g.: "user:jane@example.com", "group:eng@example.com", "domain:example.com".
+	Members []string
+	// Upstreams protected by IAP, "backend" and/or "frontend". Defaults to both.
+	// API Gateway requires both.
+	Upstreams []string
+	// ID of a custom OAuth client, e.g. "123456789-abc.apps.googleusercontent.com". Required to let
+	// users outside of the organization sign in. Defaults to the Google-managed OAuth client.
+	OAuthClientID string
+	// Secret of the custom OAuth client. Required with OAuthClientID.
+	OAuthClientSecret pulumi.StringInput
+}
+
+// HeadersArgs contains the custom headers the load balancer adds to requests and responses.
+// Header names are case-insensitive. An empty value removes a header set by the preset or
+// the shared headers.
+type HeadersArgs struct {
+	// Whether to skip the hardened preset: Strict-Transport-Security, Content-Security-Policy,
+	// X-Content-Type-Options, X-Frame-Options and Referrer-Policy response headers, and the
+	// X-Client-Region, X-Client-City, X-Client-RTT-Msec and X-TLS-Version request headers. Defaults to false.
+	DisablePreset bool
+	// Response headers added to the responses of both upstreams, overriding the preset ones.
+	// E.g.: {"Permissions-Policy": "camera=()"}
+	ResponseHeaders map[string]string
+	// Request headers forwarded to both upstreams, overriding the preset ones. Values can use
+	// load balancer variables. E.g.: {"X-Client-Country": "{client_region}"}
+	RequestHeaders map[string]string
+	// Overrides of the backend headers. With API Gateway, they apply to the gateway backend service.
+	Backend *UpstreamHeadersArgs
+	// Overrides of the frontend headers. Not used with API Gateway.
+	Frontend *UpstreamHeadersArgs
+}
+
+// UpstreamHeadersArgs contains the header overrides of a single upstream.
+type UpstreamHeadersArgs struct {
+	// Response headers overriding the preset and shared ones. E.g.: {"X-Frame-Options": "SAMEORIGIN"}
+	ResponseHeaders map[string]string
+	// Request headers overriding the preset and shared ones.
+	RequestHeaders map[string]string
+}
+
+// CDNArgs contains configuration for Cloud CDN on a load balancer backend service.
+type CDNArgs struct {
+	// Cache mode: "CACHE_ALL_STATIC", "USE_ORIGIN_HEADERS" or "FORCE_CACHE_ALL". Defaults to "CACHE_ALL_STATIC".
+	CacheMode string
+	// TTL in seconds for responses without cache directives. Defaults to 3600.
+	// Not allowed with "USE_ORIGIN_HEADERS".
+	DefaultTTL int
+	// Maximum TTL in seconds for cached responses. Defaults to 86400.
+	// Only allowed with "CACHE_ALL_STATIC".
+	MaxTTL int
+	// Whether to cache error responses. Defaults to false.
+	NegativeCaching bool
+	// TTL in seconds of cached error responses by status code. E.g.: {404: 60}.
+	// Codes must be one of 300, 301, 302, 307, 308, 404, 405, 410, 421, 451 or 501.
+	// Defaults to the GCP TTLs for each status code.
+	NegativeCachingTTLs map[int]int
+	// Seconds to serve stale content while revalidating with the origin. Defaults to 0 (disabled).
+	ServeWhileStale int
+	// Cache key policy. Defaults to the full request URL.
+	CacheKey *CDNCacheKeyArgs
+}
+
+// CDNCacheKeyArgs contains the request parts used to build the cache key.
+type CDNCacheKeyArgs struct {
+	// Whether to exclude the host from the cache key. Defaults to false.
+	ExcludeHost bool
+	// Whether to exclude the protocol from the cache key. Defaults to false.
+	ExcludeProtocol bool
+	// Whether to exclude the query string from the cache key. Defaults to false.
+	ExcludeQueryString bool
+	// Query string parameters to include in the cache key. Defaults to all.
+	QueryStringAllowlist []string
+	// HTTP headers to include in the cache key. Defaults to nil.
+	IncludeHTTPHeaders []string
+	// Cookies to include in the cache key. Defaults to nil.
+	IncludeNamedCookies []string
+}
+
+// ExternalManagedMigrationArgs contains the state of a migration from the classic to the
+// external managed load balancer.
+// See: https://cloud.google.com/load-balancing/docs/https/migrate-to-global
+type ExternalManagedMigrationArgs struct {
+	// Migration state: "PREPARE", "TEST_BY_PERCENTAGE" or "TEST_ALL_TRAFFIC". Required.
+	State string
+	// Percentage of requests served by the external managed load balancer in "TEST_BY_PERCENTAGE" state.
+	TestingPercentage float64
+}
+
+// ProxySubnetArgs contains configuration for the proxy-only subnet of the load balancer.
+type ProxySubnetArgs struct {
+	// IP CIDR range of the subnet to create. Must be /26 or larger. Defaults to "10.127.0.0/24".
+	IPCidrRange string
+	// Name of an existing proxy-only subnet in the region and network to reuse instead of
+	// creating one. E.g.: when another stack already owns it.
+	ExistingSubnet string
+}
+
+// InternalLoadBalancerArgs contains configuration for the internal Application Load Balancer.
+type InternalLoadBalancerArgs struct {
+	// Subnet of ProxyNetworkName to allocate the load balancer IP address from.
+	// Defaults to "default" in the default network, required otherwise.
+	Subnetwork string
+	// Static internal IP address in Subnetwork. Defaults to an ephemeral one.
+	IPAddress string
+	// Whether clients in other regions can reach the load balancer. Defaults to false.
+	EnableGlobalAccess bool
+	// Name of an existing private Cloud DNS zone to create the records in. Defaults to a
+	// new private zone for DomainURL visible to ProxyNetworkName.
+	PrivateDNSZone string
+	// Publishes the load balancer to other projects and VPCs with Private Service Connect. Optional.
+	PrivateServiceConnect *PrivateServiceConnectArgs
+}
+
+// PrivateServiceConnectArgs contains configuration for the Private Service Connect service attachment.
+type PrivateServiceConnectArgs struct {
+	// IP CIDR range of the NAT subnet to create for consumer connections. Defaults to "10.126.0.0/24".
+	NATSubnetCIDR string
+	// Projects allowed to connect. Defaults to accepting connections from any project.
+	ConsumerProjects []string
+	// Endpoints each consumer project can connect. Defaults to 10.
+	ConnectionLimit int
+}
+
+// SSLPolicyArgs contains the TLS settings negotiated by the HTTPS proxy with clients.
+type SSLPolicyArgs struct {
+	// Minimum TLS version: "TLS_1_0", "TLS_1_1" or "TLS_1_2". Defaults to "TLS_1_2".
+	MinTLSVersion string
+	// Profile of the policy: "COMPATIBLE", "MODERN", "RESTRICTED" or "CUSTOM". Defaults to "MODERN".
+	Profile string
+	// Cipher suites to enable with the CUSTOM profile. E.g.: "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"
+	CustomFeatures []string
+	// QUIC negotiation: "NONE", "ENABLE" or "DISABLE". Defaults to "NONE", which lets GCP decide.
+	QUICOverride string
+}
+
+// CertificateManagerArgs contains configuration for a Certificate Manager certificate.
+type CertificateManagerArgs struct {
+	// Domains on the certificate. Supports leading wildcards, e.g. "*.path2prod.dev".
+	// Defaults to all the load balancer domains.
+	Domains []string
+}
+
+// TLSCertificateArgs contains a self-managed TLS certificate for the load balancer.
+// The certificate and private key are read either from Secret Manager or from inputs.
+type TLSCertificateArgs struct {
+	// PEM-encoded certificate chain.
+	Certificate pulumi.StringInput
+	// PEM-encoded private key.
+	PrivateKey pulumi.StringInput
+	// ID of the Secret Manager secret with the PEM-encoded certificate chain.
+	CertificateSecretID pulumi.StringInput
+	// ID of the Secret Manager secret with the PEM-encoded private key.
+	PrivateKeySecretID pulumi.StringInput
+	// Version of the secrets to read. Defaults to "latest".
+	SecretVersion pulumi.StringInput
+}
+
+// HTTPSRedirectArgs contains configuration for the HTTP to HTTPS redirect entrypoint.
+type HTTPSRedirectArgs struct {
+	// Whether to disable the port 80 entrypoint. Defaults to false.
+	Disabled bool
+	// HTTP status code for the redirect. Either 301 or 308. Defaults to 301.
+	ResponseCode int
+}
+
+// RoutingArgs contains configuration for the load balancer URL map.
+type RoutingArgs struct {
+	// Ordered path rules for the domain host. A path can't be shadowed by
+	// a wildcard path of a preceding rule.
+	// Defaults to "/api/*" to the backend and "/*" to the frontend.
+	PathRules []*PathRuleArgs
+	// Upstream to route traffic to when no path rule matches. Either "backend" or "frontend".
+	// Defaults to "backend".
+	DefaultUpstream string
+	// Additional hosts with their own path rules. Optional.
+	HostRules []*HostRuleArgs
+	// Route rules for the domain host with advanced traffic management. Mutually exclusive with
+	// PathRules. Requires the "EXTERNAL_MANAGED" load balancing scheme. Optional.
+	RouteRules []*RouteRuleArgs
+}
+
+// HostRuleArgs contains configuration for a load balancer host rule.
+type HostRuleArgs struct {
+	// Hosts to match. E.g.: "admin.path2prod.dev". Required.
+	Hosts []string
+	// Ordered path rules for the hosts. Defaults to the routing PathRules.
+	PathRules []*PathRuleArgs
+	// Upstream to route traffic to when no path rule matches. Defaults to the routing DefaultUpstream.
+	DefaultUpstream string
+	// Route rules for the hosts. Mutually exclusive with PathRules. Defaults to the routing RouteRules.
+	RouteRules []*RouteRuleArgs
+}
+
+// PathRuleArgs contains configuration for a load balancer path rule.
+type PathRuleArgs struct {
+	// Paths to match. Wildcards are only allowed at the end. E.g.: "/graphql" or "/auth/*". Required.
+	Paths []string
+	// Upstream to route the matched traffic to. Either "backend" or "frontend". Required.
+	Upstream string
+	// Replaces the matched path prefix before forwarding the request to the upstream. Optional.
+	// E.g.: "/" to forward "/webhooks/stripe" as "/stripe" with path "/webhooks/*".
+	PrefixRewrite string
+}
+
+// RouteRuleArgs contains configuration for a load balancer route rule. Route rules are
+// evaluated in priority order and the first match wins.
+type RouteRuleArgs struct {
+	// Priority of the rule. Lower values are evaluated first. Must be unique within the host. Required.
+	Priority int
+	// Conditions to match. The rule matches if any of them matches. Required.
+	Matches []*RouteMatchArgs
+	// Upstream to route the matched traffic to. Either "backend" or "frontend".
+	// Mutually exclusive with WeightedUpstreams.
+	Upstream string
+	// Upstreams to split the matched traffic across by weight. Mutually exclusive with Upstream.
+	WeightedUpstreams []*WeightedUpstreamArgs
+	// Replaces the matched path prefix before forwarding the request to the upstream. Optional.
+	PrefixRewrite string
+	// Upstream to mirror the matched requests to. Responses from it are ignored. Optional.
+	MirrorUpstream string
+	// Retry policy for the matched requests. Optional.
+	Retry *RetryPolicyArgs
+}
+
+// RouteMatchArgs contains the conditions of a route rule match. All conditions must match.
+type RouteMatchArgs struct {
+	// Path prefix to match. E.g.: "/api/". Mutually exclusive with FullPath.
+	PathPrefix string
+	// Full path to match. E.g.: "/healthz". Mutually exclusive with PathPrefix.
+	FullPath string
+	// Headers to match. Optional.
+	Headers []*HeaderMatchArgs
+}
+
+// HeaderMatchArgs contains configuration to match a request header.
+type HeaderMatchArgs struct {
+	// Name of the header. Required.
+	Name string
+	// Exact value to match. Mutually exclusive with Prefix, Regex and Present.
+	Exact string
+	// Value prefix to match. Mutually exclusive with Exact, Regex and Present.
+	Prefix string
+	// RE2 regular expression the value must match. Mutually exclusive with Exact, Prefix and Present.
+	Regex string
+	// Whether to match if the header is present regardless of its value. Mutually exclusive with Exact, Prefix and Regex.
+	Present bool
+	// Whether to match requests that don't match the header condition instead. Defaults to false.
+	Invert bool
+}
+
+// WeightedUpstreamArgs contains an upstream and its share of a traffic split.
+type WeightedUpstreamArgs struct {
+	// Upstream to route traffic to. Either "backend" or "frontend". Required.
+	Upstream string
+	// Weight of the upstream between 0 and 1000. Required.
+	Weight int
+}
+
+// RetryPolicyArgs contains configuration for retrying failed requests.
+type RetryPolicyArgs struct {
+	// Number of retries. Must be greater than 0. Required.
+	NumRetries int
+	// Conditions to retry on. E.g.: "5xx", "gateway-error", "connect-failure". Defaults to "5xx".
+	Conditions []string
+	// Timeout in seconds of each try. Defaults to the backend service timeout.
+	PerTryTimeoutSeconds int
 }
 
 // APIGatewayArgs contains configuration for Google API Gateway
@@ -204,6 +741,14 @@ type CacheInstanceArgs struct {
 	MemorySizeGb int
 	// Authorized network for the Redis instance, firewall and VPC connector. Defaults to "default".
 	AuthorizedNetwork string
+	// Project hosting AuthorizedNetwork, e.g. a Shared VPC host project. The firewall is created
+	// there, and the stack project service agents are granted access to ConnectorSubnet. The
+	// instance connects with private services access, which requires an allocated IP range in
+	// AuthorizedNetwork. Requires ConnectorSubnet. Defaults to the stack project.
+	NetworkProject string
+	// Existing /28 subnet of AuthorizedNetwork for the VPC connector, e.g. shared from the host
+	// project. Mutually exclusive with ConnectorIPCidrRange. Optional.
+	ConnectorSubnet string
 	// IP CIDR range for the private traffic VPC connector. Defaults to "10.8.0.0/28".
 	ConnectorIPCidrRange string
 	// Minimum number of instances for the VPC connector. Defaults to the lowest allowed value of 2.
@@ -0,0 +1,281 @@
+package gcp
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	compute "github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/compute"
+	"github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/dns"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+const (
+	// Subnet of the default auto mode network in every region
+	defaultInternalSubnetwork = "default"
+	// Next to the default proxy-only subnet, outside of the auto mode range 10.128.0.0/9
+	defaultPSCNATSubnetCIDR = "10.126.0.0/24"
+	// Forwarding rules each consumer project can connect to the service attachment
+	defaultPSCConnectionLimit = 10
+)
+
+// usesInternalLoadBalancer returns true if the load balancer is a regional internal
+// Application Load Balancer only reachable from the VPC and its connected networks.
+//
+// See:
+// https://cloud.google.com/load-balancing/docs/l7-internal
+func usesInternalLoadBalancer(args *NetworkArgs) bool {
+	return args.LoadBalancingScheme == LoadBalancingSchemeInternalManaged
+}
+
+func applyInternalLoadBalancerDefaults(args *NetworkArgs) {
+	if args.InternalLoadBalancer == nil {
+		args.InternalLoadBalancer = &InternalLoadBalancerArgs{}
+	}
+
+	internal := args.InternalLoadBalancer
+	if internal.Subnetwork == "" && proxyNetwork(args) == "default" {
+		internal.Subnetwork = defaultInternalSubnetwork
+	}
+
+	if psc := internal.PrivateServiceConnect; psc != nil {
+		if psc.NATSubnetCIDR == "" {
+			psc.NATSubnetCIDR = defaultPSCNATSubnetCIDR
+		}
+		if psc.ConnectionLimit == 0 {
+			psc.ConnectionLimit = defaultPSCConnectionLimit
+		}
+	}
+}
+
+func validateInternalLoadBalancer(args *NetworkArgs) error {
+	internal := args.InternalLoadBalancer
+
+	if internal.Subnetwork == "" {
+		return fmt.Errorf("subnetwork is required when the proxy network is not \"default\"")
+	}
+
+	if internal.IPAddress != "" && net.ParseIP(internal.IPAddress).To4() == nil {
+		return fmt.Errorf("IP address must be a valid IPv4 address, got %q", internal.IPAddress)
+	}
+
+	// Without an existing zone, a private zone is created for the primary domain
+	if internal.PrivateDNSZone == "" {
+		for _, domain := range certificateDomains(args) {
+			if domain != args.DomainURL && !strings.HasSuffix(domain, "."+args.DomainURL) {
+				return fmt.Errorf("domain %s is outside of the private DNS zone %s, set PrivateDNSZone to an existing zone", domain, args.DomainURL)
+			}
+		}
+	}
+
+	if psc := internal.PrivateServiceConnect; psc != nil {
+		_, ipNet, err := net.ParseCIDR(psc.NATSubnetCIDR)
+		if err != nil {
+			return fmt.Errorf("invalid NAT subnet CIDR %q: %w", psc.NATSubnetCIDR, err)
+		}
+
+		if ipNet.IP.To4() == nil {
+			return fmt.Errorf("NAT subnet CIDR %s must be IPv4", psc.NATSubnetCIDR)
+		}
+
+		if psc.ConnectionLimit < 0 {
+			return fmt.Errorf("connection limit must be positive, got %d", psc.ConnectionLimit)
+		}
+	}
+
+	return nil
+}
+
+// createInternalEntrypoint reserves an internal IP address in the given subnet and creates the
+// forwarding rules of the internal load balancer in the proxy network. Clients in the VPC and
+// on-prem over Cloud Interconnect or VPN reach the load balancer through it.
+// The HTTP forwarding rule is only created if an HTTP proxy is given.
+func (f *FullStack) createInternalEntrypoint(ctx *pulumi.Context,
+	serviceName string,
+	args *NetworkArgs,
+	httpsProxy *compute.RegionTargetHttpsProxy,
+	httpProxy *compute.RegionTargetHttpProxy) (pulumi.StringOutput, error) {
+	internal := args.InternalLoadBalancer
+
+	labels := mergeLabels(f.Labels, pulumi.StringMap{
+		"load_balancer": pulumi.String("true"),
+	})
+
+	ipAddressArgs := &compute.AddressArgs{
+		Project:     pulumi.String(f.Project),
+		Region:      pulumi.String(f.Region),
+		Description: pulumi.String(fmt.Sprintf("Internal IP address for %s", serviceName)),
+		AddressType: pulumi.String("INTERNAL"),
+		Subnetwork:  pulumi.String(subnetworkPath(args.NetworkProject, f.Region, internal.Subnetwork)),
+		Labels:      labels,
+	}
+	if internal.IPAddress != "" {
+		ipAddressArgs.Address = pulumi.String(internal.IPAddress)
+	}
+	if httpProxy != nil {
+		// Internal forwarding rules can only share an IP address reserved for it
+		ipAddressArgs.Purpose = pulumi.String("SHARED_LOADBALANCER_VIP")
+	}
+
+	ipAddressName := f.NewResourceName(serviceName, "internal-ip", 63)
+	ipAddress, err := compute.NewAddress(ctx, ipAddressName, ipAddressArgs)
+	if err != nil {
+		return pulumi.StringOutput{}, fmt.Errorf("failed to reserve internal IP address: %w", err)
+	}
+	f.internalIPAddress = ipAddress
+
+	opts := f.proxySubnetDependency()
+
+	forwardingRuleName := f.NewResourceName(serviceName, "internal-https-forwarding", 63)
+	trafficRule, err := compute.NewForwardingRule(ctx, forwardingRuleName, &compute.ForwardingRuleArgs{
+		Description:         pulumi.String(fmt.Sprintf("HTTPS forwarding rule to LB internal traffic for %s", serviceName)),
+		Project:             pulumi.String(f.Project),
+		Region:              pulumi.String(f.Region),
+		PortRange:           pulumi.String("443"),
+		LoadBalancingScheme: pulumi.String(f.loadBalancingScheme),
+		Network:             pulumi.String(proxyNetwork(args)),
+		Subnetwork:          pulumi.String(subnetworkPath(args.NetworkProject, f.Region, internal.Subnetwork)),
+		Target:              httpsProxy.SelfLink,
+		IpAddress:           ipAddress.Address,
+		AllowGlobalAccess:   pulumi.Bool(internal.EnableGlobalAccess),
+		Labels:              labels,
+	}, opts...)
+	if err != nil {
+		return pulumi.StringOutput{}, fmt.Errorf("failed to create internal forwarding rule: %w", err)
+	}
+	f.regionalForwardingRule = trafficRule
+
+	if httpProxy != nil {
+		httpForwardingRuleName := f.NewResourceName(serviceName, "internal-http-forwarding", 63)
+		httpTrafficRule, err := compute.NewForwardingRule(ctx, httpForwardingRuleName, &compute.ForwardingRuleArgs{
+			Description:         pulumi.String(fmt.Sprintf("HTTP forwarding rule to redirect LB internal traffic for %s", serviceName)),
+			Project:             pulumi.String(f.Project),
+			Region:              pulumi.String(f.Region),
+			PortRange:           pulumi.String("80"),
+			LoadBalancingScheme: pulumi.String(f.loadBalancingScheme),
+			Network:             pulumi.String(proxyNetwork(args)),
+			Subnetwork:          pulumi.String(subnetworkPath(args.NetworkProject, f.Region, internal.Subnetwork)),
+			Target:              httpProxy.SelfLink,
+			IpAddress:           ipAddress.Address,
+			AllowGlobalAccess:   pulumi.Bool(internal.EnableGlobalAccess),
+			Labels:              labels,
+		}, opts...)
+		if err != nil {
+			return pulumi.StringOutput{}, fmt.Errorf("failed to create internal HTTP forwarding rule: %w", err)
+		}
+		f.regionalHTTPForwardingRule = httpTrafficRule
+	}
+
+	return ipAddress.Address, nil
+}
+
+// createPrivateDNSRecords creates an A record per load balancer domain in the private zone.
+// Without an existing zone, a private zone for the primary domain is created and made
+// visible to the proxy network.
+//
+// See:
+// https://cloud.google.com/dns/docs/zones#create-private-zone
+func (f *FullStack) createPrivateDNSRecords(ctx *pulumi.Context, serviceName string, args *NetworkArgs, lbIPAddress pulumi.StringOutput) error {
+	var managedZoneName pulumi.StringInput = pulumi.String(args.InternalLoadBalancer.PrivateDNSZone)
+
+	if args.InternalLoadBalancer.PrivateDNSZone == "" {
+		privateZoneName := f.NewResourceName(serviceName, "private-zone", 63)
+		privateZone, err := dns.NewManagedZone(ctx, privateZoneName, &dns.ManagedZoneArgs{
+			Name:        pulumi.String(privateZoneName),
+			Description: pulumi.String(fmt.Sprintf("private zone for %s", serviceName)),
+			Project:     pulumi.String(f.Project),
+			DnsName:     pulumi.String(args.DomainURL + "."),
+			Visibility:  pulumi.String("private"),
+			PrivateVisibilityConfig: &dns.ManagedZonePrivateVisibilityConfigArgs{
+				Networks: dns.ManagedZonePrivateVisibilityConfigNetworkArray{
+					&dns.ManagedZonePrivateVisibilityConfigNetworkArgs{
+						NetworkUrl: pulumi.String(networkPath(f.hostProject(args.NetworkProject), proxyNetwork(args))),
+					},
+				},
+			},
+			Labels: mergeLabels(f.Labels, nil),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create private DNS zone: %w", err)
+		}
+		f.privateDNSZone = privateZone
+
+		managedZoneName = privateZone.Name
+	}
+
+	for index, domain := range certificateDomains(args) {
+		dnsRecordName := f.NewResourceName(serviceName, "private-dns-record", 63)
+		if index > 0 {
+			dnsRecordName = f.NewResourceName(serviceName, fmt.Sprintf("private-dns-record-%s", resourceKey(domain)), 63)
+		}
+
+		dnsRecord, err := newDNSRecordSet(ctx, dnsRecordName, managedZoneName, domain, "A", lbIPAddress)
+		if err != nil {
+			return fmt.Errorf("failed to create private DNS record for %s: %w", domain, err)
+		}
+
+		if index == 0 {
+			f.dnsRecord = dnsRecord
+		}
+		f.dnsRecords = append(f.dnsRecords, dnsRecord)
+	}
+
+	return nil
+}
+
+// newServiceAttachment publishes the internal load balancer with Private Service Connect, so
+// that other projects and VPCs can reach it from an endpoint of their own. Connections are
+// accepted automatically unless consumer projects are given.
+//
+// See:
+// https://cloud.google.com/vpc/docs/about-vpc-hosted-services
+func (f *FullStack) newServiceAttachment(ctx *pulumi.Context, serviceName string, args *NetworkArgs) error {
+	psc := args.InternalLoadBalancer.PrivateServiceConnect
+
+	natSubnetName := f.NewResourceName(serviceName, "psc-nat-subnet", 63)
+	natSubnet, err := compute.NewSubnetwork(ctx, natSubnetName, &compute.SubnetworkArgs{
+		Name:        pulumi.String(natSubnetName),
+		Description: pulumi.String(fmt.Sprintf("Private Service Connect NAT subnet for %s", serviceName)),
+		Project:     pulumi.String(f.hostProject(args.NetworkProject)),
+		Region:      pulumi.String(f.Region),
+		Purpose:     pulumi.String("PRIVATE_SERVICE_CONNECT"),
+		Network:     pulumi.String(proxyNetwork(args)),
+		IpCidrRange: pulumi.String(psc.NATSubnetCIDR),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create Private Service Connect NAT subnet: %w", err)
+	}
+	f.pscNATSubnet = natSubnet
+
+	serviceAttachmentArgs := &compute.ServiceAttachmentArgs{
+		Description:          pulumi.String(fmt.Sprintf("Private Service Connect attachment for %s", serviceName)),
+		Project:              pulumi.String(f.Project),
+		Region:               pulumi.String(f.Region),
+		TargetService:        f.regionalForwardingRule.SelfLink,
+		NatSubnets:           pulumi.StringArray{natSubnet.SelfLink},
+		EnableProxyProtocol:  pulumi.Bool(false),
+		ConnectionPreference: pulumi.String("ACCEPT_AUTOMATIC"),
+	}
+
+	if len(psc.ConsumerProjects) > 0 {
+		consumers := compute.ServiceAttachmentConsumerAcceptListArray{}
+		for _, project := range psc.ConsumerProjects {
+			consumers = append(consumers, &compute.ServiceAttachmentConsumerAcceptListArgs{
+				ProjectIdOrNum:  pulumi.String(project),
+				ConnectionLimit: pulumi.Int(psc.ConnectionLimit),
+			})
+		}
+
+		serviceAttachmentArgs.ConnectionPreference = pulumi.String("ACCEPT_MANUAL")
+		serviceAttachmentArgs.ConsumerAcceptLists = consumers
+	}
+
+	serviceAttachmentName := f.NewResourceName(serviceName, "psc-attachment", 63)
+	serviceAttachment, err := compute.NewServiceAttachment(ctx, serviceAttachmentName, serviceAttachmentArgs)
+	if err != nil {
+		return fmt.Errorf("failed to create Private Service Connect service attachment: %w", err)
+	}
+	f.serviceAttachment = serviceAttachment
+
+	return nil
+}
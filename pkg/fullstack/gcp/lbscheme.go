@@ -0,0 +1,133 @@
+package gcp
+
+import (
+	"fmt"
+	"slices"
+
+	compute "github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/compute"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// Load balancing schemes of the Application Load Balancer
+const (
+	// Classic Application Load Balancer
+	LoadBalancingSchemeExternal = "EXTERNAL"
+	// Envoy-based external Application Load Balancer with advanced traffic management
+	LoadBalancingSchemeExternalManaged = "EXTERNAL_MANAGED"
+	// Envoy-based regional internal Application Load Balancer for private traffic only
+	LoadBalancingSchemeInternalManaged = "INTERNAL_MANAGED"
+)
+
+// Migration states from the classic to the external managed load balancer
+var externalManagedMigrationStates = []string{"PREPARE", "TEST_BY_PERCENTAGE", "TEST_ALL_TRAFFIC"}
+
+func applyLoadBalancingSchemeDefaults(args *NetworkArgs) {
+	if args.LoadBalancingScheme == "" {
+		args.LoadBalancingScheme = LoadBalancingSchemeExternal
+		if args.EnablePrivateTrafficOnly {
+			args.LoadBalancingScheme = LoadBalancingSchemeInternalManaged
+		}
+	}
+}
+
+func validateLoadBalancingScheme(args *NetworkArgs) error {
+	schemes := []string{LoadBalancingSchemeExternal, LoadBalancingSchemeExternalManaged, LoadBalancingSchemeInternalManaged}
+	if !slices.Contains(schemes, args.LoadBalancingScheme) {
+		return fmt.Errorf("load balancing scheme must be one of %v, got %q", schemes, args.LoadBalancingScheme)
+	}
+
+	if args.EnablePrivateTrafficOnly && !usesInternalLoadBalancer(args) {
+		return fmt.Errorf("private traffic only requires the %s load balancing scheme, got %q", LoadBalancingSchemeInternalManaged, args.LoadBalancingScheme)
+	}
+
+	if usesInternalLoadBalancer(args) && !args.EnablePrivateTrafficOnly {
+		return fmt.Errorf("%s load balancing scheme requires private traffic only", LoadBalancingSchemeInternalManaged)
+	}
+
+	if usesInternalLoadBalancer(args) && args.EnableGlobalEntrypoint {
+		return fmt.Errorf("%s load balancing scheme doesn't support the global entrypoint", LoadBalancingSchemeInternalManaged)
+	}
+
+	if usesRegionalLoadBalancer(args) {
+		if err := validateRegionalLoadBalancer(args); err != nil {
+			return fmt.Errorf("invalid regional load balancer: %w", err)
+		}
+	}
+
+	if args.LoadBalancingScheme == LoadBalancingSchemeExternal && hasRouteRules(args.Routing) {
+		return fmt.Errorf("route rules require the %s load balancing scheme", LoadBalancingSchemeExternalManaged)
+	}
+
+	if args.ExternalManagedMigration != nil {
+		if err := validateExternalManagedMigration(args); err != nil {
+			return fmt.Errorf("invalid external managed migration: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// validateExternalManagedMigration checks the migration state is valid for the current
+// scheme. The state must be TEST_ALL_TRAFFIC when switching the scheme to EXTERNAL_MANAGED.
+//
+// See:
+// https://cloud.google.com/load-balancing/docs/https/migrate-to-global
+func validateExternalManagedMigration(args *NetworkArgs) error {
+	migration := args.ExternalManagedMigration
+
+	if !slices.Contains(externalManagedMigrationStates, migration.State) {
+		return fmt.Errorf("state must be one of %v, got %q", externalManagedMigrationStates, migration.State)
+	}
+
+	if !args.EnableGlobalEntrypoint {
+		return fmt.Errorf("migration requires the global entrypoint to be enabled")
+	}
+
+	if args.LoadBalancingScheme == LoadBalancingSchemeExternalManaged && migration.State != "TEST_ALL_TRAFFIC" {
+		return fmt.Errorf("state must be TEST_ALL_TRAFFIC when the scheme is %s, got %q", LoadBalancingSchemeExternalManaged, migration.State)
+	}
+
+	if migration.State == "TEST_BY_PERCENTAGE" {
+		if migration.TestingPercentage < 0 || migration.TestingPercentage > 100 {
+			return fmt.Errorf("testing percentage must be between 0 and 100, got %v", migration.TestingPercentage)
+		}
+	} else if migration.TestingPercentage != 0 {
+		return fmt.Errorf("testing percentage can only be set in TEST_BY_PERCENTAGE state")
+	}
+
+	return nil
+}
+
+// applyExternalManagedMigration sets the migration state on a load balancer backend service
+// while moving from the classic to the external managed load balancer.
+func (f *FullStack) applyExternalManagedMigration(serviceArgs *compute.BackendServiceArgs) {
+	migration := f.externalManagedMigration
+	if migration == nil {
+		return
+	}
+
+	serviceArgs.ExternalManagedMigrationState = pulumi.String(migration.State)
+	if migration.State == "TEST_BY_PERCENTAGE" {
+		serviceArgs.ExternalManagedMigrationTestingPercentage = pulumi.Float64(migration.TestingPercentage)
+	}
+}
+
+// applyExternalManagedBackendBucketMigration sets the migration state of the backend buckets
+// on the forwarding rule routing to them. Unlike backend services, backend buckets don't have a
+// state of their own, so the forwarding rule migrates them all at once.
+func (f *FullStack) applyExternalManagedBackendBucketMigration(ruleArgs *compute.GlobalForwardingRuleArgs) {
+	migration := f.externalManagedMigration
+	if migration == nil || !f.hasBackendBuckets() {
+		return
+	}
+
+	ruleArgs.ExternalManagedBackendBucketMigrationState = pulumi.String(migration.State)
+	if migration.State == "TEST_BY_PERCENTAGE" {
+		ruleArgs.ExternalManagedBackendBucketMigrationTestingPercentage = pulumi.Float64(migration.TestingPercentage)
+	}
+}
+
+// hasBackendBuckets returns true if the URL map routes to a backend bucket.
+func (f *FullStack) hasBackendBuckets() bool {
+	return f.maintenanceBackendBucket != nil || f.errorPagesBackendBucket != nil
+}
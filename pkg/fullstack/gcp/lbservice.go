@@ -0,0 +1,110 @@
+package gcp
+
+import (
+	"fmt"
+	"slices"
+
+	compute "github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/compute"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+const (
+	// Max Cloud Run request timeout, which serverless NEG backends defer to
+	maxRequestTimeoutSeconds = 3600
+	// Max connection draining timeout of a backend service
+	maxConnectionDrainingTimeoutSeconds = 3600
+	// Log every request unless sampled down
+	defaultRequestLogSampleRate = 1.0
+	// Optional fields of the request logs
+	logOptionalModeExcludeAll = "EXCLUDE_ALL_OPTIONAL"
+	logOptionalModeIncludeAll = "INCLUDE_ALL_OPTIONAL"
+	logOptionalModeCustom     = "CUSTOM"
+)
+
+func applyLoadBalancerServiceDefaults(args *LoadBalancerServiceArgs) {
+	if args == nil || args.Logging == nil {
+		return
+	}
+
+	if args.Logging.SampleRate == nil {
+		sampleRate := defaultRequestLogSampleRate
+		args.Logging.SampleRate = &sampleRate
+	}
+
+	if args.Logging.OptionalMode == "" {
+		args.Logging.OptionalMode = logOptionalModeExcludeAll
+		if len(args.Logging.OptionalFields) > 0 {
+			args.Logging.OptionalMode = logOptionalModeCustom
+		}
+	}
+}
+
+func validateLoadBalancerService(args *LoadBalancerServiceArgs) error {
+	if args == nil {
+		return nil
+	}
+
+	if args.TimeoutSeconds < 0 || args.TimeoutSeconds > maxRequestTimeoutSeconds {
+		return fmt.Errorf("timeout must be between 0 and %d seconds, got %d", maxRequestTimeoutSeconds, args.TimeoutSeconds)
+	}
+
+	if args.ConnectionDrainingTimeoutSeconds < 0 || args.ConnectionDrainingTimeoutSeconds > maxConnectionDrainingTimeoutSeconds {
+		return fmt.Errorf("connection draining timeout must be between 0 and %d seconds, got %d",
+			maxConnectionDrainingTimeoutSeconds, args.ConnectionDrainingTimeoutSeconds)
+	}
+
+	if logging := args.Logging; logging != nil {
+		if logging.SampleRate != nil && (*logging.SampleRate < 0 || *logging.SampleRate > 1) {
+			return fmt.Errorf("log sample rate must be between 0 and 1, got %v", *logging.SampleRate)
+		}
+
+		modes := []string{logOptionalModeExcludeAll, logOptionalModeIncludeAll, logOptionalModeCustom}
+		if !slices.Contains(modes, logging.OptionalMode) {
+			return fmt.Errorf("log optional mode must be one of %v, got %q", modes, logging.OptionalMode)
+		}
+
+		if logging.OptionalMode == logOptionalModeCustom && len(logging.OptionalFields) == 0 {
+			return fmt.Errorf("log optional fields are required with the %s optional mode", logOptionalModeCustom)
+		}
+
+		if logging.OptionalMode != logOptionalModeCustom && len(logging.OptionalFields) > 0 {
+			return fmt.Errorf("log optional fields can only be set with the %s optional mode", logOptionalModeCustom)
+		}
+	}
+
+	return nil
+}
+
+// requestTimeout returns the Cloud Run request timeout of the upstream. Serverless NEG backend
+// services don't support a timeout of their own and defer to the Cloud Run one instead.
+//
+// See:
+// https://cloud.google.com/load-balancing/docs/negs/serverless-neg-concepts#limitations
+func requestTimeout(args *LoadBalancerServiceArgs) pulumi.StringPtrInput {
+	if args == nil || args.TimeoutSeconds == 0 {
+		return nil
+	}
+
+	return pulumi.String(fmt.Sprintf("%ds", args.TimeoutSeconds))
+}
+
+// applyLoadBalancerService sets the request logging and connection draining of an upstream
+// on the global backend service serving it.
+func applyLoadBalancerService(serviceArgs *compute.BackendServiceArgs, args *LoadBalancerServiceArgs) {
+	if args == nil {
+		return
+	}
+
+	if args.ConnectionDrainingTimeoutSeconds > 0 {
+		serviceArgs.ConnectionDrainingTimeoutSec = pulumi.Int(args.ConnectionDrainingTimeoutSeconds)
+	}
+
+	if logging := args.Logging; logging != nil {
+		serviceArgs.LogConfig = &compute.BackendServiceLogConfigArgs{
+			Enable:         pulumi.Bool(true),
+			SampleRate:     pulumi.Float64PtrFromPtr(logging.SampleRate),
+			OptionalMode:   pulumi.String(logging.OptionalMode),
+			OptionalFields: toStringArray(logging.OptionalFields),
+		}
+	}
+}
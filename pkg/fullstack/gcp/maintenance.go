@@ -0,0 +1,210 @@
+package gcp
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	compute "github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/compute"
+	"github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/storage"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+const (
+	// Path still routed to the backend in maintenance mode, e.g. for uptime checks
+	defaultMaintenanceHealthPath = "/healthz"
+	// Object of the maintenance bucket served for every path
+	maintenancePageObject = "index.html"
+
+	// Allowlisted clients matched by the Cloud Armor rule, within its subexpressions limit
+	// next to the health path
+	maxMaintenanceClientIPs = 4
+	// Evaluated right after the client IP allowlist of the load balancer
+	maintenanceRulePriority = ipAllowlistRulesBasePriority + 1
+)
+
+const defaultMaintenancePageHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Down for maintenance</title>
+</head>
+<body>
+<h1>Down for maintenance</h1>
+<p>We are performing scheduled maintenance and will be back shortly.</p>
+</body>
+</html>
+`
+
+func applyMaintenanceModeDefaults(args *MaintenanceModeArgs) {
+	if args.HealthPath == "" {
+		args.HealthPath = defaultMaintenanceHealthPath
+	}
+
+	if args.PageHTML == "" {
+		args.PageHTML = defaultMaintenancePageHTML
+	}
+}
+
+func validateMaintenanceMode(args *NetworkArgs) error {
+	maintenance := args.MaintenanceMode
+
+	if !strings.HasPrefix(maintenance.HealthPath, "/") || strings.Contains(maintenance.HealthPath, "*") {
+		return fmt.Errorf("health path %q must start with / and can't contain wildcards", maintenance.HealthPath)
+	}
+
+	for _, ipRange := range maintenance.ClientIPAllowlist {
+		if net.ParseIP(ipRange) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(ipRange); err != nil {
+			return fmt.Errorf("allowlisted client %q must be an IP address or a CIDR range", ipRange)
+		}
+	}
+
+	if len(maintenance.ClientIPAllowlist) == 0 {
+		return nil
+	}
+
+	// Cloud Armor sends the clients outside of the allowlist to the maintenance page
+	if !args.EnableCloudArmor {
+		return fmt.Errorf("client IP allowlist requires Cloud Armor to be enabled")
+	}
+
+	if len(maintenance.ClientIPAllowlist) > maxMaintenanceClientIPs {
+		return fmt.Errorf("at most %d allowlisted clients are supported, got %d", maxMaintenanceClientIPs, len(maintenance.ClientIPAllowlist))
+	}
+
+	return validateCELPath(maintenance.HealthPath)
+}
+
+// isInMaintenance returns true if the load balancer serves the maintenance page.
+func isInMaintenance(args *NetworkArgs) bool {
+	return args.MaintenanceMode != nil && args.MaintenanceMode.Enabled
+}
+
+// routesToMaintenancePage returns true if the URL map routes the requests to the maintenance
+// page. With a client IP allowlist, the URL map keeps its routes instead, and Cloud Armor
+// redirects the clients outside of the allowlist to the page.
+func routesToMaintenancePage(args *NetworkArgs) bool {
+	return isInMaintenance(args) && len(args.MaintenanceMode.ClientIPAllowlist) == 0
+}
+
+// maintenanceBucketName returns the name of the maintenance bucket, which the Cloud Armor
+// policy redirects to before the bucket is created.
+func (f *FullStack) maintenanceBucketName(serviceName string) string {
+	return f.NewResourceName(serviceName, "maintenance", 63)
+}
+
+// newMaintenanceBackendBucket creates a public bucket holding the maintenance page and the
+// backend bucket serving it. Both are kept while maintenance mode is disabled, so enabling it
+// only updates the URL map.
+//
+// See:
+// https://cloud.google.com/load-balancing/docs/https/ext-load-balancer-backend-buckets
+func (f *FullStack) newMaintenanceBackendBucket(ctx *pulumi.Context, serviceName string, args *MaintenanceModeArgs) (*compute.BackendBucket, error) {
+	bucketName := f.maintenanceBucketName(serviceName)
+	bucket, err := storage.NewBucket(ctx, bucketName, &storage.BucketArgs{
+		Name:     pulumi.String(bucketName),
+		Project:  pulumi.String(f.Project),
+		Location: pulumi.String(f.Region),
+		Labels: mergeLabels(f.Labels, pulumi.StringMap{
+			"load_balancer": pulumi.String("true"),
+		}),
+		ForceDestroy:             pulumi.Bool(true),
+		UniformBucketLevelAccess: pulumi.Bool(true),
+		// Every path renders the maintenance page
+		Website: &storage.BucketWebsiteArgs{
+			MainPageSuffix: pulumi.String(maintenancePageObject),
+			NotFoundPage:   pulumi.String(maintenancePageObject),
+		},
+	}, pulumi.Parent(f))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create maintenance bucket: %w", err)
+	}
+	f.maintenanceBucket = bucket
+
+	_, err = storage.NewBucketObject(ctx, fmt.Sprintf("%s-page", bucketName), &storage.BucketObjectArgs{
+		Bucket:      bucket.Name,
+		Name:        pulumi.String(maintenancePageObject),
+		Content:     pulumi.String(args.PageHTML),
+		ContentType: pulumi.String("text/html; charset=utf-8"),
+		// Browsers must not keep showing the page once maintenance is over
+		CacheControl: pulumi.String("no-store"),
+	}, pulumi.Parent(f))
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload maintenance page: %w", err)
+	}
+
+	// Backend buckets read the objects anonymously
+	_, err = storage.NewBucketIAMMember(ctx, fmt.Sprintf("%s-public", bucketName), &storage.BucketIAMMemberArgs{
+		Bucket: bucket.Name,
+		Role:   pulumi.String("roles/storage.objectViewer"),
+		Member: pulumi.String("allUsers"),
+	}, pulumi.Parent(f))
+	if err != nil {
+		return nil, fmt.Errorf("failed to grant public access to maintenance bucket: %w", err)
+	}
+
+	backendBucketName := f.NewResourceName(serviceName, "maintenance-backend", 63)
+	backendBucket, err := compute.NewBackendBucket(ctx, backendBucketName, &compute.BackendBucketArgs{
+		Description: pulumi.String(fmt.Sprintf("maintenance page of %s", serviceName)),
+		Project:     pulumi.String(f.Project),
+		BucketName:  bucket.Name,
+		EnableCdn:   pulumi.Bool(false),
+	}, pulumi.Parent(f))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create maintenance backend bucket: %w", err)
+	}
+	f.maintenanceBackendBucket = backendBucket
+
+	return backendBucket, nil
+}
+
+// newMaintenancePathMatcher replaces a path matcher in maintenance mode. The health path goes
+// to the backend and every other path to the maintenance page.
+func newMaintenancePathMatcher(name string,
+	args *MaintenanceModeArgs,
+	upstreamServices map[string]pulumi.StringOutput,
+	maintenanceService pulumi.StringOutput) *compute.URLMapPathMatcherArgs {
+	return &compute.URLMapPathMatcherArgs{
+		Name:           pulumi.String(name),
+		DefaultService: maintenanceService,
+		PathRules: compute.URLMapPathMatcherPathRuleArray{
+			&compute.URLMapPathMatcherPathRuleArgs{
+				Paths:   pulumi.StringArray{pulumi.String(args.HealthPath)},
+				Service: upstreamServices[UpstreamBackend],
+			},
+		},
+	}
+}
+
+// newMaintenanceRule redirects the clients outside of the allowlist to the maintenance page in
+// its public bucket, except for the health path. It matches the IP of the connection, so the
+// allowlist holds with proxies or a CDN in front of the load balancer, and the URL map keeps
+// routing the allowlisted clients to the upstreams.
+//
+// See:
+// https://cloud.google.com/armor/docs/rules-language-reference#attributes
+func newMaintenanceRule(args *MaintenanceModeArgs, pageURL string) *compute.SecurityPolicyRuleTypeArgs {
+	ipMatches := make([]string, 0, len(args.ClientIPAllowlist))
+	for _, ipRange := range args.ClientIPAllowlist {
+		ipMatches = append(ipMatches, ipRangeExpression(ipRange))
+	}
+
+	return &compute.SecurityPolicyRuleTypeArgs{
+		Action:      pulumi.String(cloudArmorRuleActionRedirect),
+		Description: pulumi.String("Redirect clients outside of the maintenance allowlist to the maintenance page"),
+		Priority:    pulumi.Int(maintenanceRulePriority),
+		Match: &compute.SecurityPolicyRuleMatchArgs{
+			Expr: &compute.SecurityPolicyRuleMatchExprArgs{
+				Expression: pulumi.String(fmt.Sprintf("!(%s) && request.path != '%s'", strings.Join(ipMatches, " || "), args.HealthPath)),
+			},
+		},
+		RedirectOptions: &compute.SecurityPolicyRuleRedirectOptionsArgs{
+			Type:   pulumi.String(cloudArmorRedirectExternal),
+			Target: pulumi.String(pageURL),
+		},
+	}
+}
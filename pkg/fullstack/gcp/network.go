@@ -8,15 +8,14 @@ import (
 	apigateway "github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/apigateway"
 	compute "github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/compute"
 	"github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/dns"
-	"github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/projects"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 )
 
-// deployExternalLoadBalancer sets up a global classic Application Load Balancer
-// in front of the Run Service with the following feats:
+// deployExternalLoadBalancer sets up a global classic, global external managed or regional
+// external managed Application Load Balancer in front of the Run Service with the following feats:
 //
 // - HTTPS by default with GCP managed certificate
-// - HTTP forward & redirect to HTTPs
+// - HTTP forward & redirect to HTTPs on the same IP address
 // - Optional API Gateway integration for backend traffic wrangling
 //
 // See:
@@ -37,71 +36,30 @@ func (f *FullStack) deployExternalLoadBalancer(ctx *pulumi.Context, args *Networ
 	}
 
 	if args.EnableIAP {
-		// identity platform can't be fully enabled programatically yet.
-		// make sure to enable in marketplace console.
-		//
-		// See:
-		// https://issuetracker.google.com/issues/194945691?pli=1
-		// https://stackoverflow.com/questions/67778417/enable-google-cloud-identity-platform-programmatically-no-ui
-		identityPlatformName := f.NewResourceName(endpointName, "cloudidentity", 63)
-		_, err := projects.NewService(ctx, identityPlatformName, &projects.ServiceArgs{
-			Project: pulumi.String(f.Project),
-			Service: pulumi.String("cloudidentity.googleapis.com"),
-		},
-			pulumi.RetainOnDelete(true),
-		)
+		err = f.enableIAP(ctx, endpointName, args)
 		if err != nil {
-			return fmt.Errorf("failed to enable cloudidentity API: %w", err)
+			return err
 		}
+	}
 
-		idToolkitName := f.NewResourceName(endpointName, "idtoolkit", 63)
-		_, err = projects.NewService(ctx, idToolkitName, &projects.ServiceArgs{
-			Project: pulumi.String(f.Project),
-			Service: pulumi.String("identitytoolkit.googleapis.com"),
-		},
-			pulumi.RetainOnDelete(true),
-		)
+	if usesProxySubnet(args) {
+		err = f.newProxySubnet(ctx, endpointName, args)
 		if err != nil {
-			return fmt.Errorf("failed to enable identitytoolkit API: %w", err)
-		}
-
-		// Enabling IAP requires Google project to be under an organization.
-		// This is a requirement for OAuth Brands which are created as Internal
-		// by default. To allow for external users it needs to be set to Public
-		// via the UI.
-		// An organization can be created via either Cloud Identity or Workspaces (GSuite).
-		// If using Cloud Identity, Google will require you to verify the domain provided
-		// with TXT record.
-		//
-		// See:
-		// https://cloud.google.com/iap/docs/programmatic-oauth-clients#branding
-		// https://support.google.com/cloud/answer/10311615#user-type&zippy=%2Cinternal
-		// https://cloud.google.com/resource-manager/docs/cloud-platform-resource-hierarchy#organizations
-		// iapBrandName := f.NewResourceName(serviceName, "iap-auth", 63)
-		// _, err = projects.NewService(ctx, iapBrandName, &projects.ServiceArgs{
-		// 	Project: pulumi.String(f.Project),
-		// 	Service: pulumi.String("iap.googleapis.com"),
-		// })
-		// if err != nil {
-		// 	return err
-		// }
-		// _, err = iap.NewBrand(ctx, iapBrandName, &iap.BrandArgs{
-		// 	SupportEmail:     pulumi.String(args.IAPSupportEmail),
-		// 	ApplicationTitle: pulumi.String("Cloud IAP protected Application"),
-		// 	Project:          projectService.Project,
-		// })
-		// if err != nil {
-		// 	return err
-		// }
+			return err
+		}
+	}
+
+	if usesRegionalLoadBalancer(args) {
+		return f.deployRegionalLoadBalancer(ctx, endpointName, args)
 	}
 
 	// Create NEG for either Cloud Run or API Gateway
-	lbRouteURLMap, err := f.setupTrafficRouterToUpstreamNEG(ctx, cloudArmorPolicy, endpointName, args.DomainURL, args.ProxyNetworkName, apiGateway)
+	lbRouteURLMap, err := f.setupTrafficRouterToUpstreamNEG(ctx, cloudArmorPolicy, endpointName, args, apiGateway)
 	if err != nil {
 		return fmt.Errorf("failed to setup traffic router: %w", err)
 	}
 
-	err = f.newHTTPSProxy(ctx, endpointName, args.DomainURL, args.EnablePrivateTrafficOnly, args.EnableGlobalEntrypoint, lbRouteURLMap)
+	err = f.newHTTPSProxy(ctx, endpointName, args, lbRouteURLMap)
 	if err != nil {
 		return fmt.Errorf("failed to create HTTPS proxy: %w", err)
 	}
@@ -109,53 +67,270 @@ func (f *FullStack) deployExternalLoadBalancer(ctx *pulumi.Context, args *Networ
 	return nil
 }
 
-func (f *FullStack) newHTTPSProxy(ctx *pulumi.Context, serviceName, domainURL string, privateTraffic bool, enableGlobalEntrypoint bool, backendURLMap *compute.URLMap) error {
-	tlsCertName := f.NewResourceName(serviceName, "tls-cert", 63)
-	certificate, err := compute.NewManagedSslCertificate(ctx, tlsCertName, &compute.ManagedSslCertificateArgs{
-		Description: pulumi.String(fmt.Sprintf("TLS cert for %s", serviceName)),
-		Project:     pulumi.String(f.Project),
-		Managed: &compute.ManagedSslCertificateManagedArgs{
-			Domains: pulumi.StringArray{
-				pulumi.String(domainURL),
-			},
-		},
-	})
+// validateLoadBalancerArgs sets the load balancer defaults and rejects invalid
+// settings before any resource gets created.
+func validateLoadBalancerArgs(args *NetworkArgs) error {
+	if err := validateDomains(args); err != nil {
+		return fmt.Errorf("invalid domains: %w", err)
+	}
+
+	applyLoadBalancingSchemeDefaults(args)
+	if err := validateLoadBalancingScheme(args); err != nil {
+		return fmt.Errorf("invalid load balancing scheme: %w", err)
+	}
+
+	if usesRegionalLoadBalancer(args) {
+		applyRegionalLoadBalancerDefaults(args)
+	}
+
+	if usesInternalLoadBalancer(args) {
+		applyInternalLoadBalancerDefaults(args)
+		if err := validateInternalLoadBalancer(args); err != nil {
+			return fmt.Errorf("invalid internal load balancer: %w", err)
+		}
+	}
+
+	args.Routing = applyRoutingDefaults(args.Routing)
+	if err := validateRouting(certificateDomains(args), args.Routing); err != nil {
+		return fmt.Errorf("invalid routing: %w", err)
+	}
+
+	args.HTTPSRedirect = applyHTTPSRedirectDefaults(args.HTTPSRedirect)
+	if err := validateHTTPSRedirect(args.HTTPSRedirect); err != nil {
+		return fmt.Errorf("invalid HTTPS redirect: %w", err)
+	}
+
+	if args.EnableIPv6 && !args.EnableGlobalEntrypoint {
+		return fmt.Errorf("IPv6 requires the global entrypoint to be enabled")
+	}
+
+	if args.FrontendCDN != nil {
+		// API Gateway fronts both upstreams with a single backend service, which would cache the API
+		if args.APIGateway != nil && !args.APIGateway.Disabled {
+			return fmt.Errorf("frontend CDN is not supported with API Gateway enabled")
+		}
+
+		applyCDNDefaults(args.FrontendCDN)
+		if err := validateCDN(args.FrontendCDN); err != nil {
+			return fmt.Errorf("invalid frontend CDN: %w", err)
+		}
+	}
+
+	if args.EnableIAP {
+		args.IAP = applyIAPDefaults(args.IAP)
+	}
+	if err := validateIAP(args); err != nil {
+		return fmt.Errorf("invalid IAP: %w", err)
+	}
+
+	if !usesRegionalLoadBalancer(args) {
+		args.Headers = applyHeadersDefaults(args.Headers)
+		if err := validateHeaders(args.Headers); err != nil {
+			return fmt.Errorf("invalid headers: %w", err)
+		}
+	}
+
+	if args.BotManagement != nil {
+		applyBotManagementDefaults(args)
+		if err := validateBotManagement(args); err != nil {
+			return fmt.Errorf("invalid bot management: %w", err)
+		}
+	}
+
+	if len(args.RateLimits) > 0 {
+		applyRateLimitsDefaults(args.RateLimits)
+		if err := validateRateLimits(args); err != nil {
+			return fmt.Errorf("invalid rate limits: %w", err)
+		}
+	}
+
+	if len(args.ClientIPAllowlist) > 0 || len(args.ClientIPAllowlistNamedLists) > 0 {
+		if err := validateClientIPAllowlist(args); err != nil {
+			return fmt.Errorf("invalid client IP allowlist: %w", err)
+		}
+	}
+
+	if args.AdaptiveProtection != nil {
+		applyAdaptiveProtectionDefaults(args.AdaptiveProtection)
+		if err := validateAdaptiveProtection(args); err != nil {
+			return fmt.Errorf("invalid adaptive protection: %w", err)
+		}
+	}
+
+	if args.GeoAccess != nil {
+		applyGeoAccessDefaults(args.GeoAccess)
+		if err := validateGeoAccess(args); err != nil {
+			return fmt.Errorf("invalid geo access: %w", err)
+		}
+	}
+
+	if len(args.CloudArmorRules) > 0 {
+		if err := validateCloudArmorRules(args); err != nil {
+			return fmt.Errorf("invalid Cloud Armor rules: %w", err)
+		}
+	}
+
+	if args.MaintenanceMode != nil {
+		applyMaintenanceModeDefaults(args.MaintenanceMode)
+		if err := validateMaintenanceMode(args); err != nil {
+			return fmt.Errorf("invalid maintenance mode: %w", err)
+		}
+	}
+
+	if args.ErrorPages != nil {
+		if err := validateErrorPages(args); err != nil {
+			return fmt.Errorf("invalid error pages: %w", err)
+		}
+	}
+
+	args.ProxySubnet = applyProxySubnetDefaults(args.ProxySubnet)
+	if err := validateProxySubnet(args.ProxySubnet); err != nil {
+		return fmt.Errorf("invalid proxy-only subnet: %w", err)
+	}
+
+	args.SSLPolicy = applySSLPolicyDefaults(args.SSLPolicy)
+	if err := validateSSLPolicy(args.SSLPolicy); err != nil {
+		return fmt.Errorf("invalid SSL policy: %w", err)
+	}
+
+	if args.CertificateManager != nil && args.TLSCertificate != nil {
+		return fmt.Errorf("certificate manager and self-managed TLS certificate are mutually exclusive")
+	}
+
+	if args.TLSCertificate != nil {
+		if err := validateTLSCertificate(args.TLSCertificate); err != nil {
+			return fmt.Errorf("invalid TLS certificate: %w", err)
+		}
+	}
+
+	if args.CertificateManager != nil {
+		applyCertificateManagerDefaults(args.CertificateManager, certificateDomains(args))
+		if err := validateCertificateManager(args.CertificateManager, certificateDomains(args)); err != nil {
+			return fmt.Errorf("invalid certificate manager config: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (f *FullStack) newHTTPSProxy(ctx *pulumi.Context, serviceName string, args *NetworkArgs, backendURLMap *compute.URLMap) error {
+	domains := certificateDomains(args)
+
+	sslPolicy, err := f.newSSLPolicy(ctx, serviceName, args.SSLPolicy)
 	if err != nil {
-		return fmt.Errorf("failed to create managed SSL certificate: %w", err)
+		return fmt.Errorf("failed to attach SSL policy to HTTPS proxy: %w", err)
 	}
+	f.sslPolicy = sslPolicy
 
-	f.certificate = certificate
+	httpsProxyArgs := &compute.TargetHttpsProxyArgs{
+		Description:  pulumi.String(fmt.Sprintf("proxy to LB traffic for %s", serviceName)),
+		Project:      pulumi.String(f.Project),
+		UrlMap:       backendURLMap.SelfLink,
+		SslPolicy:    sslPolicy.SelfLink,
+		QuicOverride: pulumi.String(args.SSLPolicy.QUICOverride),
+	}
 
-	httpsProxyName := f.NewResourceName(serviceName, "https-proxy", 63)
-	httpsProxy, err := compute.NewTargetHttpsProxy(ctx, httpsProxyName, &compute.TargetHttpsProxyArgs{
-		Description: pulumi.String(fmt.Sprintf("proxy to LB traffic for %s", serviceName)),
-		Project:     pulumi.String(f.Project),
-		UrlMap:      backendURLMap.SelfLink,
-		SslCertificates: pulumi.StringArray{
+	if args.CertificateManager != nil {
+		certificateMap, err := f.newCertificateMap(ctx, serviceName, args.CertificateManager)
+		if err != nil {
+			return fmt.Errorf("failed to create certificate map: %w", err)
+		}
+
+		httpsProxyArgs.CertificateMap = pulumi.Sprintf("//certificatemanager.googleapis.com/%s", certificateMap.ID())
+	} else if args.TLSCertificate != nil {
+		sslCertificate, err := f.newSelfManagedCertificate(ctx, serviceName, args.TLSCertificate)
+		if err != nil {
+			return err
+		}
+
+		f.sslCertificate = sslCertificate
+
+		httpsProxyArgs.SslCertificates = pulumi.StringArray{
+			sslCertificate.SelfLink,
+		}
+	} else {
+		tlsCertName := f.NewResourceName(serviceName, "tls-cert", 63)
+		certificate, err := compute.NewManagedSslCertificate(ctx, tlsCertName, &compute.ManagedSslCertificateArgs{
+			Description: pulumi.String(fmt.Sprintf("TLS cert for %s", serviceName)),
+			Project:     pulumi.String(f.Project),
+			Managed: &compute.ManagedSslCertificateManagedArgs{
+				// All the domains are added to the certificate as SANs
+				Domains: toStringArray(domains),
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create managed SSL certificate: %w", err)
+		}
+
+		f.certificate = certificate
+
+		httpsProxyArgs.SslCertificates = pulumi.StringArray{
 			certificate.SelfLink,
-		},
-	})
+		}
+	}
+
+	httpsProxyName := f.NewResourceName(serviceName, "https-proxy", 63)
+	httpsProxy, err := compute.NewTargetHttpsProxy(ctx, httpsProxyName, httpsProxyArgs)
 	if err != nil {
 		return fmt.Errorf("failed to create target HTTPS proxy: %w", err)
 	}
 
-	if !privateTraffic {
-		var lbIPAddress pulumi.StringOutput
-		if enableGlobalEntrypoint {
-			lbIPAddress, err = f.createGlobalInternetEntrypoint(ctx, serviceName, httpsProxy)
-		} else {
-			lbIPAddress, err = f.createRegionalInternetEntrypoint(ctx, serviceName, httpsProxy)
+	var httpProxy *compute.TargetHttpProxy
+	if !args.HTTPSRedirect.Disabled {
+		httpProxy, err = f.newHTTPRedirectProxy(ctx, serviceName, args.HTTPSRedirect)
+		if err != nil {
+			return fmt.Errorf("failed to create HTTP redirect proxy: %w", err)
 		}
+	}
+
+	var lbIPAddress pulumi.StringOutput
+	if args.EnableGlobalEntrypoint {
+		lbIPAddress, err = f.createGlobalInternetEntrypoint(ctx, serviceName, httpsProxy, httpProxy)
+	} else {
+		lbIPAddress, err = f.createRegionalInternetEntrypoint(ctx, serviceName, httpsProxy, httpProxy)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create internet entrypoint: %w", err)
+	}
+
+	var lbIPv6Address pulumi.StringOutput
+	if args.EnableIPv6 {
+		lbIPv6Address, err = f.createGlobalIPv6Entrypoint(ctx, serviceName, httpsProxy, httpProxy)
 		if err != nil {
-			return fmt.Errorf("failed to create internet entrypoint: %w", err)
+			return fmt.Errorf("failed to create IPv6 internet entrypoint: %w", err)
 		}
+	}
 
-		// Create DNS record for the LB IP address
-		dnsRecord, dnsErr := f.createDNSRecord(ctx, serviceName, domainURL, lbIPAddress)
+	return f.createLoadBalancerDNSRecords(ctx, serviceName, args, lbIPAddress, lbIPv6Address)
+}
+
+// createLoadBalancerDNSRecords creates the A records, and the AAAA records if IPv6 is
+// enabled, pointing every certificate domain at the load balancer.
+func (f *FullStack) createLoadBalancerDNSRecords(ctx *pulumi.Context, serviceName string, args *NetworkArgs, lbIPAddress, lbIPv6Address pulumi.StringOutput) error {
+	for index, domain := range certificateDomains(args) {
+		dnsRecordName := f.NewResourceName(serviceName, "dns-record", 63)
+		if index > 0 {
+			dnsRecordName = f.NewResourceName(serviceName, fmt.Sprintf("dns-record-%s", resourceKey(domain)), 63)
+		}
+
+		dnsRecord, dnsErr := f.createDNSRecord(ctx, dnsRecordName, domain, "A", lbIPAddress)
 		if dnsErr != nil {
-			return fmt.Errorf("failed to create DNS record: %w", dnsErr)
+			return fmt.Errorf("failed to create DNS record for %s: %w", domain, dnsErr)
+		}
+
+		if index == 0 {
+			f.dnsRecord = dnsRecord
+		}
+		f.dnsRecords = append(f.dnsRecords, dnsRecord)
+
+		if args.EnableIPv6 {
+			ipv6DNSRecordName := f.NewResourceName(serviceName, fmt.Sprintf("dns-record-ipv6-%s", resourceKey(domain)), 63)
+			ipv6DNSRecord, dnsErr := f.createDNSRecord(ctx, ipv6DNSRecordName, domain, "AAAA", lbIPv6Address)
+			if dnsErr != nil {
+				return fmt.Errorf("failed to create AAAA DNS record for %s: %w", domain, dnsErr)
+			}
+			f.ipv6DNSRecords = append(f.ipv6DNSRecords, ipv6DNSRecord)
 		}
-		f.dnsRecord = dnsRecord
 	}
 
 	return nil
@@ -163,43 +338,19 @@ func (f *FullStack) newHTTPSProxy(ctx *pulumi.Context, serviceName, domainURL st
 
 func (f *FullStack) setupTrafficRouterToUpstreamNEG(ctx *pulumi.Context,
 	policy *compute.SecurityPolicy,
-	serviceName,
-	domainURL,
-	network string,
+	serviceName string,
+	args *NetworkArgs,
 	apiGateway *apigateway.Gateway) (*compute.URLMap, error) {
-	// create proxy-only subnet required by Cloud Run to get traffic from the LB
-	// See:
-	// https://cloud.google.com/load-balancing/docs/https#proxy-only-subnet
-	trafficNetwork := network
-	if trafficNetwork == "" {
-		trafficNetwork = "default"
-	}
-
-	proxySubnetName := f.NewResourceName(serviceName, "proxy-subnet", 63)
-	_, err := compute.NewSubnetwork(ctx, proxySubnetName, &compute.SubnetworkArgs{
-		Name:        pulumi.String(proxySubnetName),
-		Description: pulumi.String(fmt.Sprintf("proxy-only subnet for %s traffic", serviceName)),
-		Project:     pulumi.String(f.Project),
-		Region:      pulumi.String(f.Region),
-		Purpose:     pulumi.String("REGIONAL_MANAGED_PROXY"),
-		Network:     pulumi.String(trafficNetwork),
-		// Extended subnetworks in auto subnet mode networks cannot overlap with 10.128.0.0/9
-		IpCidrRange: pulumi.String("10.127.0.0/24"),
-		Role:        pulumi.String("ACTIVE"),
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to create proxy-only subnet: %w", err)
-	}
-
 	var urlMap *compute.URLMap
+	var err error
 
 	if f.gatewayEnabled {
-		urlMap, err = f.routeTrafficToGateway(ctx, policy, serviceName, domainURL, apiGateway)
+		urlMap, err = f.routeTrafficToGateway(ctx, policy, serviceName, args, apiGateway)
 		if err != nil {
 			return nil, fmt.Errorf("failed to route traffic to API Gateway: %w", err)
 		}
 	} else {
-		urlMap, err = f.routeTrafficToCloudRunInstances(ctx, policy, serviceName, domainURL)
+		urlMap, err = f.routeTrafficToCloudRunInstances(ctx, policy, serviceName, args)
 		if err != nil {
 			return nil, fmt.Errorf("failed to route traffic to Cloud Run: %w", err)
 		}
@@ -214,26 +365,80 @@ func (f *FullStack) setupTrafficRouterToUpstreamNEG(ctx *pulumi.Context,
 // and returns the URL map.
 func (f *FullStack) routeTrafficToGateway(ctx *pulumi.Context,
 	policy *compute.SecurityPolicy,
-	serviceName,
-	_ string,
+	serviceName string,
+	args *NetworkArgs,
 	apiGateway *apigateway.Gateway) (*compute.URLMap, error) {
 
 	// Create NEG for API Gateway
-	lbGatewayBackendService, err := f.createGatewayNEG(ctx, policy, serviceName, apiGateway)
+	lbGatewayBackendService, err := f.createGatewayNEG(ctx, policy, serviceName, args, apiGateway)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create API Gateway NEG: %w", err)
 	}
 
 	urlMapName := f.NewResourceName(serviceName, "url-map", 63)
 
-	// Create URL map for Gateway NEG
-	urlMap, err := compute.NewURLMap(ctx, urlMapName, &compute.URLMapArgs{
+	urlMapArgs := &compute.URLMapArgs{
 		Description: pulumi.String(fmt.Sprintf("URL map to LB traffic for %s", serviceName)),
 		Project:     pulumi.String(f.Project),
 		// All traffic is deferred to the Gateway NEG
 		DefaultService: lbGatewayBackendService.SelfLink,
 		// TODO set host rules to match DNS
-	})
+	}
+
+	hostRules := compute.URLMapHostRuleArray{}
+	pathMatchers := compute.URLMapPathMatcherArray{}
+
+	if args.MaintenanceMode != nil {
+		maintenanceBucket, err := f.newMaintenanceBackendBucket(ctx, serviceName, args.MaintenanceMode)
+		if err != nil {
+			return nil, err
+		}
+
+		if routesToMaintenancePage(args) {
+			// The gateway serves both upstreams
+			gatewayServices := map[string]pulumi.StringOutput{
+				UpstreamBackend:  lbGatewayBackendService.SelfLink,
+				UpstreamFrontend: lbGatewayBackendService.SelfLink,
+			}
+			paths := newMaintenancePathMatcher("traffic-paths", args.MaintenanceMode, gatewayServices, maintenanceBucket.SelfLink)
+
+			urlMapArgs.DefaultService = maintenanceBucket.SelfLink
+			hostRules = append(hostRules, &compute.URLMapHostRuleArgs{
+				Hosts:       toStringArray(servedDomains(args)),
+				PathMatcher: paths.Name,
+			})
+			pathMatchers = append(pathMatchers, paths)
+		}
+	}
+
+	if args.ErrorPages != nil {
+		errorPagesBucket, err := f.newErrorPagesBackendBucket(ctx, serviceName, args.ErrorPages)
+		if err != nil {
+			return nil, err
+		}
+
+		// The gateway serves the backend pages
+		urlMapArgs.DefaultCustomErrorResponsePolicy = newErrorResponsePolicy(args.ErrorPages, UpstreamBackend, errorPagesBucket.SelfLink)
+	}
+
+	// Non-canonical domains are redirected before reaching the Gateway
+	var redirectedHosts []*urlMapHost
+	for _, host := range newURLMapHosts(args) {
+		if host.redirect != nil {
+			redirectedHosts = append(redirectedHosts, host)
+		}
+	}
+	redirectHostRules, redirectPathMatchers := toURLMapHostRules(redirectedHosts, nil)
+	hostRules = append(hostRules, redirectHostRules...)
+	pathMatchers = append(pathMatchers, redirectPathMatchers...)
+
+	if len(hostRules) > 0 {
+		urlMapArgs.HostRules = hostRules
+		urlMapArgs.PathMatchers = pathMatchers
+	}
+
+	// Create URL map for Gateway NEG
+	urlMap, err := compute.NewURLMap(ctx, urlMapName, urlMapArgs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create URL map for Gateway: %w", err)
 	}
@@ -246,6 +451,7 @@ func (f *FullStack) routeTrafficToGateway(ctx *pulumi.Context,
 func (f *FullStack) createGatewayNEG(ctx *pulumi.Context,
 	policy *compute.SecurityPolicy,
 	serviceName string,
+	args *NetworkArgs,
 	apiGateway *apigateway.Gateway) (*compute.BackendService, error) {
 	// This feature is currently in preview. The NEG gets to fail attached to the API Gateway.
 	// See:
@@ -274,7 +480,7 @@ func (f *FullStack) createGatewayNEG(ctx *pulumi.Context,
 	lbGatewayServiceArgs := &compute.BackendServiceArgs{
 		Description:         pulumi.String(fmt.Sprintf("service backend for %s", serviceName)),
 		Project:             pulumi.String(f.Project),
-		LoadBalancingScheme: pulumi.String("EXTERNAL"),
+		LoadBalancingScheme: pulumi.String(f.loadBalancingScheme),
 		Backends: compute.BackendServiceBackendArray{
 			&compute.BackendServiceBackendArgs{
 				// Point the LB backend to the Gateway NEG
@@ -288,6 +494,13 @@ func (f *FullStack) createGatewayNEG(ctx *pulumi.Context,
 		lbGatewayServiceArgs.SecurityPolicy = policy.SelfLink
 	}
 
+	f.applyExternalManagedMigration(lbGatewayServiceArgs)
+	// The gateway fronts the backend API
+	applyLoadBalancerService(lbGatewayServiceArgs, f.backendLBServiceArgs)
+	applyHeaders(lbGatewayServiceArgs, args.Headers, UpstreamBackend)
+	// Validation ensures IAP protects both upstreams behind the gateway
+	f.applyIAP(lbGatewayServiceArgs, args, UpstreamBackend)
+
 	// Create the LB's backend service for Gateway NEG
 	backendServiceName := f.NewResourceName(serviceName, "gateway-backend-service", 63)
 	lbGatewayBackendService, err := compute.NewBackendService(ctx, backendServiceName, lbGatewayServiceArgs)
@@ -295,16 +508,17 @@ func (f *FullStack) createGatewayNEG(ctx *pulumi.Context,
 		return nil, fmt.Errorf("failed to create Gateway backend service: %w", err)
 	}
 
+	err = f.grantIAPAccess(ctx, serviceName, args, UpstreamBackend, lbGatewayBackendService)
+	if err != nil {
+		return nil, err
+	}
+
 	return lbGatewayBackendService, nil
 }
 
-// createCloudRunNEGs creates Network Endpoint Groups (NEGs) for Cloud Run instances
-// and returns the associated backend and frontend services.
-func (f *FullStack) createCloudRunNEGs(ctx *pulumi.Context,
-	policy *compute.SecurityPolicy,
-	serviceName string) (*compute.BackendService, *compute.BackendService, error) {
-	// No Gateway. Create NEGs for backend and frontendCloud Run instances
-
+// createCloudRunServerlessNEGs creates the serverless Network Endpoint Groups (NEGs)
+// for the backend and frontend Cloud Run instances.
+func (f *FullStack) createCloudRunServerlessNEGs(ctx *pulumi.Context, serviceName string) error {
 	cloudrunBackendNegName := f.NewResourceName(serviceName, "backend-cloudrun-neg", 63)
 	backendNeg, err := compute.NewRegionNetworkEndpointGroup(ctx, cloudrunBackendNegName, &compute.RegionNetworkEndpointGroupArgs{
 		Description:         pulumi.String(fmt.Sprintf("NEG to route LB traffic to %s", serviceName)),
@@ -316,7 +530,7 @@ func (f *FullStack) createCloudRunNEGs(ctx *pulumi.Context,
 		},
 	})
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create backend Cloud Run NEG: %w", err)
+		return fmt.Errorf("failed to create backend Cloud Run NEG: %w", err)
 	}
 	f.backendNeg = backendNeg
 
@@ -331,40 +545,73 @@ func (f *FullStack) createCloudRunNEGs(ctx *pulumi.Context,
 		},
 	})
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create frontend Cloud Run NEG: %w", err)
+		return fmt.Errorf("failed to create frontend Cloud Run NEG: %w", err)
 	}
 	f.frontendNeg = frontendNeg
 
-	lbBackendServiceArgs := &compute.BackendServiceArgs{
-		Description:         pulumi.String(fmt.Sprintf("service backend for %s", serviceName)),
-		Project:             pulumi.String(f.Project),
-		LoadBalancingScheme: pulumi.String("EXTERNAL"),
-		Backends: compute.BackendServiceBackendArray{
-			&compute.BackendServiceBackendArgs{
-				// Point the LB backend to the Gateway NEG
-				Group: f.backendNeg.SelfLink,
-			},
-		},
+	return nil
+}
+
+// newUpstreamServiceArgs returns the args of the backend service routing to the Cloud Run NEG of
+// an upstream, with its request logging, connection draining and IAP. Global and regional load
+// balancers share them.
+func (f *FullStack) newUpstreamServiceArgs(description string, args *NetworkArgs, upstream string) *compute.BackendServiceArgs {
+	neg, lbServiceArgs := f.backendNeg, f.backendLBServiceArgs
+	if upstream == UpstreamFrontend {
+		neg, lbServiceArgs = f.frontendNeg, f.frontendLBServiceArgs
 	}
 
-	lbFrontendServiceArgs := &compute.BackendServiceArgs{
-		Description:         pulumi.String(fmt.Sprintf("service backend for %s", serviceName)),
+	serviceArgs := &compute.BackendServiceArgs{
+		Description:         pulumi.String(description),
 		Project:             pulumi.String(f.Project),
-		LoadBalancingScheme: pulumi.String("EXTERNAL"),
+		LoadBalancingScheme: pulumi.String(f.loadBalancingScheme),
 		Backends: compute.BackendServiceBackendArray{
 			&compute.BackendServiceBackendArgs{
-				// Point the LB backend to the Gateway NEG
-				Group: f.frontendNeg.SelfLink,
+				Group: neg.SelfLink,
 			},
 		},
 	}
 
+	applyLoadBalancerService(serviceArgs, lbServiceArgs)
+	f.applyIAP(serviceArgs, args, upstream)
+
+	return serviceArgs
+}
+
+// createCloudRunNEGs creates Network Endpoint Groups (NEGs) for Cloud Run instances
+// and returns the associated backend and frontend services.
+func (f *FullStack) createCloudRunNEGs(ctx *pulumi.Context,
+	policy *compute.SecurityPolicy,
+	serviceName string,
+	args *NetworkArgs) (*compute.BackendService, *compute.BackendService, error) {
+	// No Gateway. Create NEGs for backend and frontendCloud Run instances
+	err := f.createCloudRunServerlessNEGs(ctx, serviceName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	description := fmt.Sprintf("service backend for %s", serviceName)
+	lbBackendServiceArgs := f.newUpstreamServiceArgs(description, args, UpstreamBackend)
+	lbFrontendServiceArgs := f.newUpstreamServiceArgs(description, args, UpstreamFrontend)
+
 	// Attach Cloud Armor policy if enabled
 	if policy != nil {
 		lbBackendServiceArgs.SecurityPolicy = policy.SelfLink
 		lbFrontendServiceArgs.SecurityPolicy = policy.SelfLink
 	}
 
+	f.applyExternalManagedMigration(lbBackendServiceArgs)
+	f.applyExternalManagedMigration(lbFrontendServiceArgs)
+
+	applyHeaders(lbBackendServiceArgs, args.Headers, UpstreamBackend)
+	applyHeaders(lbFrontendServiceArgs, args.Headers, UpstreamFrontend)
+
+	// Only the frontend is cached. The backend API is never cached.
+	if args.FrontendCDN != nil {
+		lbFrontendServiceArgs.EnableCdn = pulumi.Bool(true)
+		lbFrontendServiceArgs.CdnPolicy = newCDNPolicy(args.FrontendCDN)
+	}
+
 	// Create the LB backends - They'll be attached to the URL map
 	backendServiceName := f.NewResourceName(serviceName, "cloudrun-backend-service", 63)
 	backendService, err := compute.NewBackendService(ctx, backendServiceName, lbBackendServiceArgs)
@@ -378,6 +625,26 @@ func (f *FullStack) createCloudRunNEGs(ctx *pulumi.Context,
 		return nil, nil, fmt.Errorf("failed to create frontend service for NEG: %w", err)
 	}
 
+	f.backendLBService = backendService
+	f.frontendLBService = frontendService
+
+	if f.backendCanaryService != nil {
+		_, err = f.createCanaryNEG(ctx, serviceName, args, lbBackendServiceArgs)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	err = f.grantIAPAccess(ctx, serviceName, args, UpstreamBackend, backendService)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = f.grantIAPAccess(ctx, serviceName, args, UpstreamFrontend, frontendService)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	return backendService, frontendService, nil
 }
 
@@ -385,57 +652,86 @@ func (f *FullStack) createCloudRunNEGs(ctx *pulumi.Context,
 // and returns the URL map.
 func (f *FullStack) routeTrafficToCloudRunInstances(ctx *pulumi.Context,
 	policy *compute.SecurityPolicy,
-	serviceName,
-	domainURL string) (*compute.URLMap, error) {
+	serviceName string,
+	args *NetworkArgs) (*compute.URLMap, error) {
+	routing := args.Routing
 
 	// Create NEGs for Cloud Run instances
-	backendService, frontendService, err := f.createCloudRunNEGs(ctx, policy, serviceName)
+	backendService, frontendService, err := f.createCloudRunNEGs(ctx, policy, serviceName, args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Cloud Run NEGs: %w", err)
 	}
 
+	upstreamServices := map[string]pulumi.StringOutput{
+		UpstreamBackend:  backendService.SelfLink,
+		UpstreamFrontend: frontendService.SelfLink,
+	}
+	if f.backendCanaryLBService != nil {
+		upstreamServices[upstreamCanary] = f.backendCanaryLBService.SelfLink
+	}
+
 	urlMapName := f.NewResourceName(serviceName, "url-map", 63)
 
-	paths := &compute.URLMapPathMatcherArgs{
-		Name:           pulumi.String("traffic-paths"),
-		DefaultService: backendService.SelfLink,
-		PathRules: compute.URLMapPathMatcherPathRuleArray{
-			&compute.URLMapPathMatcherPathRuleArgs{
-				Paths: pulumi.StringArray{
-					// TODO make me configurable
-					pulumi.String("/api/*"),
-				},
-				Service: backendService.SelfLink,
-			},
-			&compute.URLMapPathMatcherPathRuleArgs{
-				Paths: pulumi.StringArray{
-					// TODO make me configurable
-					pulumi.String("/*"),
-				},
-				Service: frontendService.SelfLink,
-			},
-		},
+	defaultService := upstreamServices[routing.DefaultUpstream]
+	var maintenanceService pulumi.StringOutput
+	if args.MaintenanceMode != nil {
+		maintenanceBucket, err := f.newMaintenanceBackendBucket(ctx, serviceName, args.MaintenanceMode)
+		if err != nil {
+			return nil, err
+		}
+		maintenanceService = maintenanceBucket.SelfLink
+
+		if routesToMaintenancePage(args) {
+			defaultService = maintenanceService
+		}
+	}
+
+	var errorPagesService pulumi.StringOutput
+	if args.ErrorPages != nil {
+		errorPagesBucket, err := f.newErrorPagesBackendBucket(ctx, serviceName, args.ErrorPages)
+		if err != nil {
+			return nil, err
+		}
+		errorPagesService = errorPagesBucket.SelfLink
+	}
+
+	// Path matchers of the served hosts, replaced by the maintenance page in maintenance mode
+	newServedPathMatcher := func(host *urlMapHost) *compute.URLMapPathMatcherArgs {
+		name, rules, routeRules, defaultUpstream := host.pathMatcher, host.pathRules, host.routeRules, host.defaultUpstream
+
+		// The canary takes a share of the routes to the backend
+		if isCanaryActive(f.backendCanaryArgs) {
+			routeRules = newCanaryRouteRules(flattenRouteRules(rules, routeRules, defaultUpstream), f.backendCanaryArgs)
+			rules = nil
+		}
+
+		if routesToMaintenancePage(args) {
+			return newMaintenancePathMatcher(name, args.MaintenanceMode, upstreamServices, maintenanceService)
+		}
+
+		pathMatcher := newPathMatcher(name, rules, routeRules, defaultUpstream, upstreamServices)
+		if args.ErrorPages != nil {
+			applyErrorPages(pathMatcher, rules, routeRules, defaultUpstream, args.ErrorPages, errorPagesService)
+		}
+
+		return pathMatcher
 	}
 
-	urlMap, err := compute.NewURLMap(ctx, urlMapName, &compute.URLMapArgs{
+	hostRules, pathMatchers := toURLMapHostRules(newURLMapHosts(args), newServedPathMatcher)
+
+	urlMapArgs := &compute.URLMapArgs{
 		Description: pulumi.String(fmt.Sprintf("URL map to LB traffic for %s", serviceName)),
 		Project:     pulumi.String(f.Project),
-		// Default to the backend if no path matches
-		DefaultService: backendService.SelfLink,
-		PathMatchers: compute.URLMapPathMatcherArray{
-			paths,
-		},
-		// Host rules (can be customized for your domain)
-		HostRules: compute.URLMapHostRuleArray{
-			&compute.URLMapHostRuleArgs{
-				Hosts: pulumi.StringArray{
-					// Favor domain URL over "*" to avoid host header attacks
-					pulumi.String(domainURL),
-				},
-				PathMatcher: paths.Name,
-			},
-		},
-	})
+		// Default to the configured upstream if no host matches
+		DefaultService: defaultService,
+		PathMatchers:   pathMatchers,
+		HostRules:      hostRules,
+	}
+	if args.ErrorPages != nil {
+		urlMapArgs.DefaultCustomErrorResponsePolicy = newErrorResponsePolicy(args.ErrorPages, routing.DefaultUpstream, errorPagesService)
+	}
+
+	urlMap, err := compute.NewURLMap(ctx, urlMapName, urlMapArgs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create URL map for Cloud Run: %w", err)
 	}
@@ -443,8 +739,9 @@ func (f *FullStack) routeTrafficToCloudRunInstances(ctx *pulumi.Context,
 	return urlMap, nil
 }
 
-// createGlobalInternetEntrypoint creates a global IP address and forwarding rule for external traffic
-func (f *FullStack) createGlobalInternetEntrypoint(ctx *pulumi.Context, serviceName string, httpsProxy *compute.TargetHttpsProxy) (pulumi.StringOutput, error) {
+// createGlobalInternetEntrypoint creates a global IP address and forwarding rules for external traffic.
+// The HTTP forwarding rule is only created if an HTTP proxy is given.
+func (f *FullStack) createGlobalInternetEntrypoint(ctx *pulumi.Context, serviceName string, httpsProxy *compute.TargetHttpsProxy, httpProxy *compute.TargetHttpProxy) (pulumi.StringOutput, error) {
 	labels := mergeLabels(f.Labels, pulumi.StringMap{
 		"load_balancer": pulumi.String("true"),
 	})
@@ -463,15 +760,18 @@ func (f *FullStack) createGlobalInternetEntrypoint(ctx *pulumi.Context, serviceN
 
 	// https://cloud.google.com/load-balancing/docs/https#forwarding-rule
 	forwardingRuleName := f.NewResourceName(serviceName, "https-forwarding", 63)
-	trafficRule, err := compute.NewGlobalForwardingRule(ctx, forwardingRuleName, &compute.GlobalForwardingRuleArgs{
+	forwardingRuleArgs := &compute.GlobalForwardingRuleArgs{
 		Description:         pulumi.String(fmt.Sprintf("HTTPS forwarding rule to LB traffic for %s", serviceName)),
 		Project:             pulumi.String(f.Project),
 		PortRange:           pulumi.String("443"),
-		LoadBalancingScheme: pulumi.String("EXTERNAL"),
+		LoadBalancingScheme: pulumi.String(f.loadBalancingScheme),
 		Target:              httpsProxy.SelfLink,
 		IpAddress:           ipAddress.Address,
 		Labels:              labels,
-	})
+	}
+	f.applyExternalManagedBackendBucketMigration(forwardingRuleArgs)
+
+	trafficRule, err := compute.NewGlobalForwardingRule(ctx, forwardingRuleName, forwardingRuleArgs)
 	if err != nil {
 		return pulumi.StringOutput{}, fmt.Errorf("failed to create global forwarding rule: %w", err)
 	}
@@ -479,12 +779,92 @@ func (f *FullStack) createGlobalInternetEntrypoint(ctx *pulumi.Context, serviceN
 	// Store the forwarding rule in the FullStack struct
 	f.globalForwardingRule = trafficRule
 
+	if httpProxy != nil {
+		// Plain HTTP traffic lands on the same IP to get redirected to HTTPS
+		httpForwardingRuleName := f.NewResourceName(serviceName, "http-forwarding", 63)
+		httpTrafficRule, err := compute.NewGlobalForwardingRule(ctx, httpForwardingRuleName, &compute.GlobalForwardingRuleArgs{
+			Description:         pulumi.String(fmt.Sprintf("HTTP forwarding rule to redirect LB traffic for %s", serviceName)),
+			Project:             pulumi.String(f.Project),
+			PortRange:           pulumi.String("80"),
+			LoadBalancingScheme: pulumi.String(f.loadBalancingScheme),
+			Target:              httpProxy.SelfLink,
+			IpAddress:           ipAddress.Address,
+			Labels:              labels,
+		})
+		if err != nil {
+			return pulumi.StringOutput{}, fmt.Errorf("failed to create global HTTP forwarding rule: %w", err)
+		}
+
+		f.globalHTTPForwardingRule = httpTrafficRule
+	}
+
+	return ipAddress.Address, nil
+}
+
+// createGlobalIPv6Entrypoint reserves a global IPv6 address and creates forwarding rules
+// to the same proxies as the IPv4 entrypoint for dual-stack clients.
+//
+// See:
+// https://cloud.google.com/load-balancing/docs/ipv6
+func (f *FullStack) createGlobalIPv6Entrypoint(ctx *pulumi.Context, serviceName string, httpsProxy *compute.TargetHttpsProxy, httpProxy *compute.TargetHttpProxy) (pulumi.StringOutput, error) {
+	labels := mergeLabels(f.Labels, pulumi.StringMap{
+		"load_balancer": pulumi.String("true"),
+	})
+
+	ipAddressName := f.NewResourceName(serviceName, "global-ipv6", 63)
+	ipAddress, err := compute.NewGlobalAddress(ctx, ipAddressName, &compute.GlobalAddressArgs{
+		Project:     pulumi.String(f.Project),
+		Description: pulumi.String(fmt.Sprintf("IPv6 address for %s", serviceName)),
+		IpVersion:   pulumi.String("IPV6"),
+		Labels:      labels,
+	})
+	if err != nil {
+		return pulumi.StringOutput{}, fmt.Errorf("failed to reserve global IPv6 address: %w", err)
+	}
+	f.globalIPv6Address = ipAddress
+
+	forwardingRuleName := f.NewResourceName(serviceName, "https-forwarding-ipv6", 63)
+	forwardingRuleArgs := &compute.GlobalForwardingRuleArgs{
+		Description:         pulumi.String(fmt.Sprintf("HTTPS IPv6 forwarding rule to LB traffic for %s", serviceName)),
+		Project:             pulumi.String(f.Project),
+		PortRange:           pulumi.String("443"),
+		LoadBalancingScheme: pulumi.String(f.loadBalancingScheme),
+		Target:              httpsProxy.SelfLink,
+		IpAddress:           ipAddress.Address,
+		Labels:              labels,
+	}
+	f.applyExternalManagedBackendBucketMigration(forwardingRuleArgs)
+
+	trafficRule, err := compute.NewGlobalForwardingRule(ctx, forwardingRuleName, forwardingRuleArgs)
+	if err != nil {
+		return pulumi.StringOutput{}, fmt.Errorf("failed to create global IPv6 forwarding rule: %w", err)
+	}
+	f.globalIPv6ForwardingRule = trafficRule
+
+	if httpProxy != nil {
+		httpForwardingRuleName := f.NewResourceName(serviceName, "http-forwarding-ipv6", 63)
+		httpTrafficRule, err := compute.NewGlobalForwardingRule(ctx, httpForwardingRuleName, &compute.GlobalForwardingRuleArgs{
+			Description:         pulumi.String(fmt.Sprintf("HTTP IPv6 forwarding rule to redirect LB traffic for %s", serviceName)),
+			Project:             pulumi.String(f.Project),
+			PortRange:           pulumi.String("80"),
+			LoadBalancingScheme: pulumi.String(f.loadBalancingScheme),
+			Target:              httpProxy.SelfLink,
+			IpAddress:           ipAddress.Address,
+			Labels:              labels,
+		})
+		if err != nil {
+			return pulumi.StringOutput{}, fmt.Errorf("failed to create global IPv6 HTTP forwarding rule: %w", err)
+		}
+		f.globalIPv6HTTPForwardingRule = httpTrafficRule
+	}
+
 	return ipAddress.Address, nil
 }
 
-// createRegionalInternetEntrypoint creates a regional IP address and regional forwarding rule
-// for external traffic (Classic Application Load Balancer in Standard Tier)
-func (f *FullStack) createRegionalInternetEntrypoint(ctx *pulumi.Context, serviceName string, httpsProxy *compute.TargetHttpsProxy) (pulumi.StringOutput, error) {
+// createRegionalInternetEntrypoint creates a regional IP address and regional forwarding rules
+// for external traffic (Classic Application Load Balancer in Standard Tier).
+// The HTTP forwarding rule is only created if an HTTP proxy is given.
+func (f *FullStack) createRegionalInternetEntrypoint(ctx *pulumi.Context, serviceName string, httpsProxy *compute.TargetHttpsProxy, httpProxy *compute.TargetHttpProxy) (pulumi.StringOutput, error) {
 	labels := mergeLabels(f.Labels, pulumi.StringMap{
 		"load_balancer": pulumi.String("true"),
 	})
@@ -510,7 +890,7 @@ func (f *FullStack) createRegionalInternetEntrypoint(ctx *pulumi.Context, servic
 		Project:             pulumi.String(f.Project),
 		Region:              pulumi.String(f.Region),
 		PortRange:           pulumi.String("443"),
-		LoadBalancingScheme: pulumi.String("EXTERNAL"),
+		LoadBalancingScheme: pulumi.String(LoadBalancingSchemeExternal),
 		Target:              httpsProxy.SelfLink,
 		IpAddress:           ipAddress.Address,
 		// Classic ALB regional requires Standard tier
@@ -524,6 +904,27 @@ func (f *FullStack) createRegionalInternetEntrypoint(ctx *pulumi.Context, servic
 	// Store the forwarding rule in the FullStack struct
 	f.regionalForwardingRule = trafficRule
 
+	if httpProxy != nil {
+		// Plain HTTP traffic lands on the same IP to get redirected to HTTPS
+		httpForwardingRuleName := f.NewResourceName(serviceName, "regional-http-forwarding", 63)
+		httpTrafficRule, err := compute.NewForwardingRule(ctx, httpForwardingRuleName, &compute.ForwardingRuleArgs{
+			Description:         pulumi.String(fmt.Sprintf("HTTP forwarding rule to redirect LB traffic for %s", serviceName)),
+			Project:             pulumi.String(f.Project),
+			Region:              pulumi.String(f.Region),
+			PortRange:           pulumi.String("80"),
+			LoadBalancingScheme: pulumi.String(LoadBalancingSchemeExternal),
+			Target:              httpProxy.SelfLink,
+			IpAddress:           ipAddress.Address,
+			NetworkTier:         pulumi.StringPtr("STANDARD"),
+			Labels:              labels,
+		})
+		if err != nil {
+			return pulumi.StringOutput{}, fmt.Errorf("failed to create regional HTTP forwarding rule: %w", err)
+		}
+
+		f.regionalHTTPForwardingRule = httpTrafficRule
+	}
+
 	return ipAddress.Address, nil
 }
 
@@ -541,6 +942,11 @@ func (f *FullStack) lookupDNSZone(ctx *pulumi.Context, domainURL string) (string
 	var targetZoneName string
 	var targetZoneDNSName string
 	for _, zone := range managedZones.ManagedZones {
+		// Private zones only resolve inside their VPC networks
+		if zone.Visibility == "private" {
+			continue
+		}
+
 		// Check if the domain URL ends with the zone's DNS name (with or without trailing dot)
 		zoneDNSName := strings.TrimSuffix(zone.DnsName, ".")
 		if strings.HasSuffix(domainURL, zoneDNSName) {
@@ -565,16 +971,19 @@ func (f *FullStack) lookupDNSZone(ctx *pulumi.Context, domainURL string) (string
 	return targetZoneName, nil
 }
 
-// createDNSRecord creates a DNS A record for the given domain and IP address
-func (f *FullStack) createDNSRecord(ctx *pulumi.Context, serviceName, domainURL string, ipAddress pulumi.StringOutput) (*dns.RecordSet, error) {
+// createDNSRecord creates a DNS A or AAAA record for the given domain and IP address
+func (f *FullStack) createDNSRecord(ctx *pulumi.Context, dnsRecordName, domainURL, recordType string, ipAddress pulumi.StringOutput) (*dns.RecordSet, error) {
 	// Look up the DNS managed zone for the domain
 	managedZoneName, err := f.lookupDNSZone(ctx, domainURL)
 	if err != nil {
 		return nil, err
 	}
 
-	dnsRecordName := f.NewResourceName(serviceName, "dns-record", 63)
+	return newDNSRecordSet(ctx, dnsRecordName, pulumi.String(managedZoneName), domainURL, recordType, ipAddress)
+}
 
+// newDNSRecordSet creates a DNS A or AAAA record for the given domain and IP address in the managed zone
+func newDNSRecordSet(ctx *pulumi.Context, dnsRecordName string, managedZoneName pulumi.StringInput, domainURL, recordType string, ipAddress pulumi.StringOutput) (*dns.RecordSet, error) {
 	// Ensure domain URL ends with a trailing dot for DNS compliance
 	dnsName := domainURL
 	if !strings.HasSuffix(dnsName, ".") {
@@ -582,9 +991,9 @@ func (f *FullStack) createDNSRecord(ctx *pulumi.Context, serviceName, domainURL
 	}
 
 	dnsRecord, err := dns.NewRecordSet(ctx, dnsRecordName, &dns.RecordSetArgs{
-		ManagedZone: pulumi.String(managedZoneName),
+		ManagedZone: managedZoneName,
 		Name:        pulumi.String(dnsName),
-		Type:        pulumi.String("A"),
+		Type:        pulumi.String(recordType),
 		Ttl:         pulumi.Int(3600),
 		Rrdatas:     pulumi.StringArray{ipAddress},
 	})
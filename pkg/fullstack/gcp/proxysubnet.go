@@ -0,0 +1,141 @@
+package gcp
+
+import (
+	"fmt"
+	"net"
+
+	compute "github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/compute"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+const (
+	proxySubnetPurpose = "REGIONAL_MANAGED_PROXY"
+
+	// Extended subnetworks in auto subnet mode networks cannot overlap with 10.128.0.0/9
+	defaultProxySubnetCIDR = "10.127.0.0/24"
+	// Proxy-only subnets need at least 64 addresses
+	maxProxySubnetPrefixLength = 26
+)
+
+// usesProxySubnet returns true if the load balancer runs Envoy proxies in a proxy-only
+// subnet, as the regional external and internal ones do. Neither the classic nor the
+// global external managed load balancers do.
+//
+// See:
+// https://cloud.google.com/load-balancing/docs/proxy-only-subnets
+func usesProxySubnet(args *NetworkArgs) bool {
+	return args.LoadBalancingScheme != LoadBalancingSchemeExternal && !args.EnableGlobalEntrypoint
+}
+
+// proxyNetwork returns the VPC network hosting the load balancer proxies, qualified with
+// its project when hosted in a Shared VPC host project.
+func proxyNetwork(args *NetworkArgs) string {
+	if args.ProxyNetworkName == "" {
+		return networkPath(args.NetworkProject, "default")
+	}
+
+	return networkPath(args.NetworkProject, args.ProxyNetworkName)
+}
+
+func applyProxySubnetDefaults(args *ProxySubnetArgs) *ProxySubnetArgs {
+	if args == nil {
+		args = &ProxySubnetArgs{}
+	}
+
+	if args.IPCidrRange == "" && args.ExistingSubnet == "" {
+		args.IPCidrRange = defaultProxySubnetCIDR
+	}
+
+	return args
+}
+
+func validateProxySubnet(args *ProxySubnetArgs) error {
+	if args.ExistingSubnet != "" {
+		if args.IPCidrRange != "" {
+			return fmt.Errorf("IP CIDR range can't be set when using an existing subnet")
+		}
+
+		return nil
+	}
+
+	_, ipNet, err := net.ParseCIDR(args.IPCidrRange)
+	if err != nil {
+		return fmt.Errorf("invalid IP CIDR range %q: %w", args.IPCidrRange, err)
+	}
+
+	if ipNet.IP.To4() == nil {
+		return fmt.Errorf("IP CIDR range %s must be IPv4", args.IPCidrRange)
+	}
+
+	if prefixLength, _ := ipNet.Mask.Size(); prefixLength > maxProxySubnetPrefixLength {
+		return fmt.Errorf("IP CIDR range %s must be /%d or larger", args.IPCidrRange, maxProxySubnetPrefixLength)
+	}
+
+	return nil
+}
+
+// newProxySubnet creates the proxy-only subnet for the load balancer proxies, unless an
+// existing one is referenced. A region and VPC network can only have one active
+// proxy-only subnet, so stacks sharing them must reuse it.
+func (f *FullStack) newProxySubnet(ctx *pulumi.Context, serviceName string, args *NetworkArgs) error {
+	if args.ProxySubnet.ExistingSubnet != "" {
+		return f.readProxySubnet(ctx, serviceName, args)
+	}
+
+	proxySubnetName := f.NewResourceName(serviceName, "proxy-subnet", 63)
+	proxySubnet, err := compute.NewSubnetwork(ctx, proxySubnetName, &compute.SubnetworkArgs{
+		Name:        pulumi.String(proxySubnetName),
+		Description: pulumi.String(fmt.Sprintf("proxy-only subnet for %s traffic", serviceName)),
+		Project:     pulumi.String(f.hostProject(args.NetworkProject)),
+		Region:      pulumi.String(f.Region),
+		Purpose:     pulumi.String(proxySubnetPurpose),
+		Network:     pulumi.String(proxyNetwork(args)),
+		IpCidrRange: pulumi.String(args.ProxySubnet.IPCidrRange),
+		Role:        pulumi.String("ACTIVE"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create proxy-only subnet: %w", err)
+	}
+
+	f.proxySubnet = proxySubnet
+
+	return nil
+}
+
+// readProxySubnet reads the existing proxy-only subnet into the stack, so the forwarding rules
+// depend on it like on a subnet of their own.
+func (f *FullStack) readProxySubnet(ctx *pulumi.Context, serviceName string, args *NetworkArgs) error {
+	existingSubnet := args.ProxySubnet.ExistingSubnet
+	subnetID := fmt.Sprintf("projects/%s/regions/%s/subnetworks/%s", f.hostProject(args.NetworkProject), f.Region, existingSubnet)
+
+	proxySubnetName := f.NewResourceName(serviceName, "existing-proxy-subnet", 63)
+	proxySubnet, err := compute.GetSubnetwork(ctx, proxySubnetName, pulumi.ID(subnetID), nil)
+	if err != nil {
+		return fmt.Errorf("failed to read proxy-only subnet %s: %w", existingSubnet, err)
+	}
+
+	f.proxySubnet = proxySubnet
+
+	return nil
+}
+
+// proxySubnetDependency makes the forwarding rules wait for the proxy-only subnet, since they
+// fail to create until the region has one. They also fail early if an existing subnet isn't a
+// proxy-only one, instead of at apply time.
+func (f *FullStack) proxySubnetDependency() []pulumi.ResourceOption {
+	if f.proxySubnet == nil {
+		return nil
+	}
+
+	proxySubnet := f.proxySubnet
+	ready := pulumi.All(proxySubnet.Name, proxySubnet.Purpose).ApplyT(func(values []interface{}) ([]pulumi.Resource, error) {
+		purpose := values[1].(string)
+		if purpose != proxySubnetPurpose {
+			return nil, fmt.Errorf("subnet %s must have the %s purpose, got %q", values[0], proxySubnetPurpose, purpose)
+		}
+
+		return []pulumi.Resource{proxySubnet}, nil
+	}).(pulumi.ResourceArrayOutput)
+
+	return []pulumi.ResourceOption{pulumi.DependsOnInputs(ready)}
+}
@@ -1,6 +1,11 @@
 package gcp
 
-import "github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
 
 func setDefaultStringArray(input []string, defaultValue []string) pulumi.StringArrayOutput {
 	if input == nil {
@@ -13,3 +18,21 @@ func setDefaultStringArray(input []string, defaultValue []string) pulumi.StringA
 
 	return result.ToStringArrayOutput()
 }
+
+func toStringArray(input []string) pulumi.StringArray {
+	result := make(pulumi.StringArray, 0, len(input))
+	for _, v := range input {
+		result = append(result, pulumi.String(v))
+	}
+
+	return result
+}
+
+// resourceKey returns a short hash of a value, e.g. a domain or an IAM member, to name its
+// resources. Unlike the value itself, it's a valid resource name of fixed length, so long
+// values can't collide once truncated, and it doesn't change when the values are reordered.
+func resourceKey(value string) string {
+	hash := sha256.Sum256([]byte(value))
+
+	return hex.EncodeToString(hash[:4])
+}
@@ -0,0 +1,202 @@
+package gcp
+
+import (
+	"fmt"
+	"slices"
+
+	compute "github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/compute"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+const (
+	rateLimitActionThrottle     = "throttle"
+	rateLimitActionRateBasedBan = "rate_based_ban"
+
+	defaultRateLimitIntervalSeconds    = 60
+	defaultRateLimitBanDurationSeconds = 600
+	defaultRateLimitExceedStatusCode   = 429
+	defaultRateLimitEnforceOnKey       = "IP"
+
+	// Rate limit rules are evaluated after the preconfigured WAF rules, since requests under
+	// the threshold are allowed right away
+	rateLimitRulesBasePriority = 100
+	maxRateLimits              = 10
+)
+
+var (
+	// See:
+	// https://cloud.google.com/armor/docs/rate-limiting-overview#rate-limit-thresholds
+	rateLimitIntervals = []int{10, 30, 60, 120, 180, 240, 300, 600, 900, 1200, 1800, 2700, 3600}
+	// See:
+	// https://cloud.google.com/armor/docs/rate-limiting-overview#identifying_clients_for_rate_limiting
+	rateLimitEnforceOnKeys  = []string{"ALL", "IP", "HTTP_HEADER", "XFF_IP", "HTTP_COOKIE"}
+	rateLimitExceedStatuses = []int{403, 404, 429, 502}
+)
+
+func applyRateLimitsDefaults(rateLimits []*RateLimitArgs) {
+	for _, rateLimit := range rateLimits {
+		if rateLimit == nil {
+			continue
+		}
+
+		if rateLimit.Action == "" {
+			rateLimit.Action = rateLimitActionThrottle
+		}
+
+		if rateLimit.IntervalSeconds == 0 {
+			rateLimit.IntervalSeconds = defaultRateLimitIntervalSeconds
+		}
+
+		if rateLimit.Action == rateLimitActionRateBasedBan && rateLimit.BanDurationSeconds == 0 {
+			rateLimit.BanDurationSeconds = defaultRateLimitBanDurationSeconds
+		}
+
+		if rateLimit.EnforceOnKey == "" {
+			rateLimit.EnforceOnKey = defaultRateLimitEnforceOnKey
+		}
+
+		if rateLimit.ExceedStatusCode == 0 {
+			rateLimit.ExceedStatusCode = defaultRateLimitExceedStatusCode
+		}
+	}
+}
+
+func validateRateLimits(args *NetworkArgs) error {
+	if !args.EnableCloudArmor {
+		return fmt.Errorf("rate limits require Cloud Armor to be enabled")
+	}
+
+	if len(args.RateLimits) > maxRateLimits {
+		return fmt.Errorf("at most %d rate limits are supported, got %d", maxRateLimits, len(args.RateLimits))
+	}
+
+	for index, rateLimit := range args.RateLimits {
+		if rateLimit == nil {
+			return fmt.Errorf("rate limit %d is empty", index)
+		}
+
+		if err := validateRateLimit(rateLimit); err != nil {
+			return fmt.Errorf("rate limit %d: %w", index, err)
+		}
+	}
+
+	return nil
+}
+
+func validateRateLimit(rateLimit *RateLimitArgs) error {
+	if rateLimit.Action != rateLimitActionThrottle && rateLimit.Action != rateLimitActionRateBasedBan {
+		return fmt.Errorf("action must be %q or %q, got %q", rateLimitActionThrottle, rateLimitActionRateBasedBan, rateLimit.Action)
+	}
+
+	if rateLimit.ThresholdCount <= 0 {
+		return fmt.Errorf("threshold count must be greater than 0, got %d", rateLimit.ThresholdCount)
+	}
+
+	if !slices.Contains(rateLimitIntervals, rateLimit.IntervalSeconds) {
+		return fmt.Errorf("interval must be one of %v seconds, got %d", rateLimitIntervals, rateLimit.IntervalSeconds)
+	}
+
+	if rateLimit.Action == rateLimitActionThrottle && rateLimit.BanDurationSeconds != 0 {
+		return fmt.Errorf("ban duration is only supported with the %q action", rateLimitActionRateBasedBan)
+	}
+
+	if rateLimit.BanDurationSeconds < 0 {
+		return fmt.Errorf("ban duration must be greater than 0, got %d", rateLimit.BanDurationSeconds)
+	}
+
+	if !slices.Contains(rateLimitEnforceOnKeys, rateLimit.EnforceOnKey) {
+		return fmt.Errorf("enforce on key must be one of %v, got %q", rateLimitEnforceOnKeys, rateLimit.EnforceOnKey)
+	}
+
+	keyNamed := rateLimit.EnforceOnKey == "HTTP_HEADER" || rateLimit.EnforceOnKey == "HTTP_COOKIE"
+	if keyNamed && rateLimit.EnforceOnKeyName == "" {
+		return fmt.Errorf("enforce on key name is required with %s", rateLimit.EnforceOnKey)
+	}
+	if !keyNamed && rateLimit.EnforceOnKeyName != "" {
+		return fmt.Errorf("enforce on key name is only supported with HTTP_HEADER or HTTP_COOKIE")
+	}
+
+	if !slices.Contains(rateLimitExceedStatuses, rateLimit.ExceedStatusCode) {
+		return fmt.Errorf("exceed status code must be one of %v, got %d", rateLimitExceedStatuses, rateLimit.ExceedStatusCode)
+	}
+
+	if rateLimit.Path != "" && rateLimit.Expression != "" {
+		return fmt.Errorf("only one of path or expression can be set")
+	}
+
+	if rateLimit.Path != "" {
+		if err := validateCELPath(rateLimit.Path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newRateLimitRules creates the "throttle" and "rate_based_ban" rules of the rate limits, in the
+// order they're declared in. Requests under the threshold are allowed, and the ones over it are
+// denied, or banned for the ban duration with "rate_based_ban".
+//
+// See:
+// https://cloud.google.com/armor/docs/rate-limiting-overview
+// https://cloud.google.com/armor/docs/configure-rate-limiting
+func newRateLimitRules(rateLimits []*RateLimitArgs) compute.SecurityPolicyRuleTypeArray {
+	var rateLimitRules compute.SecurityPolicyRuleTypeArray
+	for index, rateLimit := range rateLimits {
+		options := &compute.SecurityPolicyRuleRateLimitOptionsArgs{
+			ConformAction: pulumi.String("allow"),
+			ExceedAction:  pulumi.String(fmt.Sprintf("deny(%d)", rateLimit.ExceedStatusCode)),
+			EnforceOnKey:  pulumi.String(rateLimit.EnforceOnKey),
+			RateLimitThreshold: &compute.SecurityPolicyRuleRateLimitOptionsRateLimitThresholdArgs{
+				Count:       pulumi.Int(rateLimit.ThresholdCount),
+				IntervalSec: pulumi.Int(rateLimit.IntervalSeconds),
+			},
+		}
+		if rateLimit.EnforceOnKeyName != "" {
+			options.EnforceOnKeyName = pulumi.String(rateLimit.EnforceOnKeyName)
+		}
+		if rateLimit.Action == rateLimitActionRateBasedBan {
+			options.BanDurationSec = pulumi.Int(rateLimit.BanDurationSeconds)
+		}
+
+		description := rateLimit.Description
+		if description == "" {
+			description = fmt.Sprintf("%s rule %d", rateLimit.Action, index)
+		}
+
+		rateLimitRules = append(rateLimitRules, &compute.SecurityPolicyRuleTypeArgs{
+			Action:           pulumi.String(rateLimit.Action),
+			Description:      pulumi.String(description),
+			Priority:         pulumi.Int(rateLimitRulesBasePriority + index),
+			Match:            newRateLimitMatch(rateLimit),
+			RateLimitOptions: options,
+		})
+	}
+
+	return rateLimitRules
+}
+
+// newRateLimitMatch scopes a rate limit to a path prefix or a CEL expression, or to all requests.
+func newRateLimitMatch(rateLimit *RateLimitArgs) *compute.SecurityPolicyRuleMatchArgs {
+	expression := rateLimit.Expression
+	if rateLimit.Path != "" {
+		expression = pathPrefixExpression(rateLimit.Path)
+	}
+
+	if expression != "" {
+		return &compute.SecurityPolicyRuleMatchArgs{
+			Expr: &compute.SecurityPolicyRuleMatchExprArgs{
+				Expression: pulumi.String(expression),
+			},
+		}
+	}
+
+	return &compute.SecurityPolicyRuleMatchArgs{
+		VersionedExpr: pulumi.String("SRC_IPS_V1"),
+		Config: &compute.SecurityPolicyRuleMatchConfigArgs{
+			SrcIpRanges: pulumi.StringArray{
+				pulumi.String("*"),
+			},
+		},
+	}
+}
@@ -0,0 +1,151 @@
+package gcp
+
+import (
+	"fmt"
+	"net/http"
+
+	compute "github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/compute"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// URL map redirect response codes by HTTP status
+var redirectResponseCodes = map[int]string{
+	http.StatusMovedPermanently:  "MOVED_PERMANENTLY_DEFAULT",
+	http.StatusPermanentRedirect: "PERMANENT_REDIRECT",
+}
+
+func applyHTTPSRedirectDefaults(args *HTTPSRedirectArgs) *HTTPSRedirectArgs {
+	if args == nil {
+		args = &HTTPSRedirectArgs{}
+	}
+
+	if args.ResponseCode == 0 {
+		args.ResponseCode = http.StatusMovedPermanently
+	}
+
+	return args
+}
+
+func validateHTTPSRedirect(args *HTTPSRedirectArgs) error {
+	if _, ok := redirectResponseCodes[args.ResponseCode]; !ok {
+		return fmt.Errorf("redirect response code must be %d or %d, got %d",
+			http.StatusMovedPermanently, http.StatusPermanentRedirect, args.ResponseCode)
+	}
+
+	return nil
+}
+
+// urlRedirect is an HTTPS redirect of the URL map keeping the path and query of the request.
+// Redirects to the same host when no host is given.
+type urlRedirect struct {
+	host         string
+	responseCode string
+}
+
+func newHTTPSRedirect(args *HTTPSRedirectArgs) *urlRedirect {
+	return &urlRedirect{
+		responseCode: redirectResponseCodes[args.ResponseCode],
+	}
+}
+
+func (r *urlRedirect) hostRedirect() pulumi.StringPtrInput {
+	if r.host == "" {
+		return nil
+	}
+
+	return pulumi.String(r.host)
+}
+
+func (r *urlRedirect) toURLMapRedirect() *compute.URLMapDefaultUrlRedirectArgs {
+	return &compute.URLMapDefaultUrlRedirectArgs{
+		HostRedirect:         r.hostRedirect(),
+		HttpsRedirect:        pulumi.Bool(true),
+		RedirectResponseCode: pulumi.String(r.responseCode),
+		StripQuery:           pulumi.Bool(false),
+	}
+}
+
+func (r *urlRedirect) toRegionURLMapRedirect() *compute.RegionUrlMapDefaultUrlRedirectArgs {
+	return &compute.RegionUrlMapDefaultUrlRedirectArgs{
+		HostRedirect:         r.hostRedirect(),
+		HttpsRedirect:        pulumi.Bool(true),
+		RedirectResponseCode: pulumi.String(r.responseCode),
+		StripQuery:           pulumi.Bool(false),
+	}
+}
+
+func (r *urlRedirect) toPathMatcherRedirect() *compute.URLMapPathMatcherDefaultUrlRedirectArgs {
+	return &compute.URLMapPathMatcherDefaultUrlRedirectArgs{
+		HostRedirect:         r.hostRedirect(),
+		HttpsRedirect:        pulumi.Bool(true),
+		RedirectResponseCode: pulumi.String(r.responseCode),
+		StripQuery:           pulumi.Bool(false),
+	}
+}
+
+func (r *urlRedirect) toRegionPathMatcherRedirect() *compute.RegionUrlMapPathMatcherDefaultUrlRedirectArgs {
+	return &compute.RegionUrlMapPathMatcherDefaultUrlRedirectArgs{
+		HostRedirect:         r.hostRedirect(),
+		HttpsRedirect:        pulumi.Bool(true),
+		RedirectResponseCode: pulumi.String(r.responseCode),
+		StripQuery:           pulumi.Bool(false),
+	}
+}
+
+// newHTTPRedirectProxy creates a redirect-only URL map and the target HTTP proxy
+// to send plain HTTP traffic to HTTPS.
+//
+// See:
+// https://cloud.google.com/load-balancing/docs/https/setting-up-global-http-https-redirect
+func (f *FullStack) newHTTPRedirectProxy(ctx *pulumi.Context, serviceName string, args *HTTPSRedirectArgs) (*compute.TargetHttpProxy, error) {
+	redirectURLMapName := f.NewResourceName(serviceName, "https-redirect", 63)
+	redirectURLMap, err := compute.NewURLMap(ctx, redirectURLMapName, &compute.URLMapArgs{
+		Description:        pulumi.String(fmt.Sprintf("URL map to redirect HTTP traffic to HTTPS for %s", serviceName)),
+		Project:            pulumi.String(f.Project),
+		DefaultUrlRedirect: newHTTPSRedirect(args).toURLMapRedirect(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTPS redirect URL map: %w", err)
+	}
+	f.httpsRedirectURLMap = redirectURLMap
+
+	httpProxyName := f.NewResourceName(serviceName, "http-proxy", 63)
+	httpProxy, err := compute.NewTargetHttpProxy(ctx, httpProxyName, &compute.TargetHttpProxyArgs{
+		Description: pulumi.String(fmt.Sprintf("proxy to redirect HTTP traffic to HTTPS for %s", serviceName)),
+		Project:     pulumi.String(f.Project),
+		UrlMap:      redirectURLMap.SelfLink,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create target HTTP proxy: %w", err)
+	}
+
+	return httpProxy, nil
+}
+
+// newRegionHTTPRedirectProxy creates the regional redirect-only URL map and target HTTP
+// proxy of regional load balancers.
+func (f *FullStack) newRegionHTTPRedirectProxy(ctx *pulumi.Context, serviceName string, args *HTTPSRedirectArgs) (*compute.RegionTargetHttpProxy, error) {
+	redirectURLMapName := f.NewResourceName(serviceName, "regional-https-redirect", 63)
+	redirectURLMap, err := compute.NewRegionUrlMap(ctx, redirectURLMapName, &compute.RegionUrlMapArgs{
+		Description:        pulumi.String(fmt.Sprintf("URL map to redirect HTTP traffic to HTTPS for %s", serviceName)),
+		Project:            pulumi.String(f.Project),
+		Region:             pulumi.String(f.Region),
+		DefaultUrlRedirect: newHTTPSRedirect(args).toRegionURLMapRedirect(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create regional HTTPS redirect URL map: %w", err)
+	}
+
+	httpProxyName := f.NewResourceName(serviceName, "regional-http-proxy", 63)
+	httpProxy, err := compute.NewRegionTargetHttpProxy(ctx, httpProxyName, &compute.RegionTargetHttpProxyArgs{
+		Description: pulumi.String(fmt.Sprintf("proxy to redirect HTTP traffic to HTTPS for %s", serviceName)),
+		Project:     pulumi.String(f.Project),
+		Region:      pulumi.String(f.Region),
+		UrlMap:      redirectURLMap.SelfLink,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create regional target HTTP proxy: %w", err)
+	}
+
+	return httpProxy, nil
+}
@@ -0,0 +1,347 @@
+package gcp
+
+import (
+	"fmt"
+
+	compute "github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/compute"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// usesRegionalLoadBalancer returns true if the load balancer is a regional external or
+// internal Application Load Balancer, with the proxies, URL map, certificate and TLS
+// termination all in the stack region.
+//
+// See:
+// https://cloud.google.com/load-balancing/docs/https#regional-connections
+func usesRegionalLoadBalancer(args *NetworkArgs) bool {
+	return args.LoadBalancingScheme != LoadBalancingSchemeExternal && !args.EnableGlobalEntrypoint
+}
+
+// validateRegionalLoadBalancer rejects the features only available to global load balancers.
+func validateRegionalLoadBalancer(args *NetworkArgs) error {
+	if args.APIGateway != nil && !args.APIGateway.Disabled {
+		return fmt.Errorf("regional load balancer doesn't support API Gateway")
+	}
+
+	if args.FrontendCDN != nil {
+		return fmt.Errorf("regional load balancer doesn't support the frontend CDN")
+	}
+
+	if args.EnableCloudArmor {
+		return fmt.Errorf("regional load balancer doesn't support Cloud Armor")
+	}
+
+	// Regional backend services don't support custom headers
+	if args.Headers != nil {
+		return fmt.Errorf("regional load balancer doesn't support custom headers")
+	}
+
+	// Regional URL maps can't route to backend buckets
+	if args.MaintenanceMode != nil {
+		return fmt.Errorf("regional load balancer doesn't support maintenance mode")
+	}
+
+	if args.ErrorPages != nil {
+		return fmt.Errorf("regional load balancer doesn't support custom error pages")
+	}
+
+	if args.SSLPolicy != nil && args.SSLPolicy.QUICOverride != "" && args.SSLPolicy.QUICOverride != "NONE" {
+		return fmt.Errorf("regional load balancer doesn't support QUIC override")
+	}
+
+	return nil
+}
+
+// applyRegionalLoadBalancerDefaults provisions a regional Certificate Manager certificate
+// when no certificate is given, since Google-managed compute certificates are global only.
+func applyRegionalLoadBalancerDefaults(args *NetworkArgs) {
+	if args.CertificateManager == nil && args.TLSCertificate == nil {
+		args.CertificateManager = &CertificateManagerArgs{}
+	}
+}
+
+// deployRegionalLoadBalancer sets up a regional external or internal Application Load Balancer
+// in front of the Cloud Run instances. Its Envoy proxies run in the proxy-only subnet.
+//
+// See:
+// https://cloud.google.com/load-balancing/docs/https/setting-up-reg-ext-https-serverless
+// https://cloud.google.com/load-balancing/docs/l7-internal/setting-up-l7-internal-serverless
+func (f *FullStack) deployRegionalLoadBalancer(ctx *pulumi.Context, serviceName string, args *NetworkArgs) error {
+	urlMap, err := f.routeTrafficToRegionalCloudRunInstances(ctx, serviceName, args)
+	if err != nil {
+		return fmt.Errorf("failed to setup regional traffic router: %w", err)
+	}
+
+	err = f.newRegionHTTPSProxy(ctx, serviceName, args, urlMap)
+	if err != nil {
+		return fmt.Errorf("failed to create regional HTTPS proxy: %w", err)
+	}
+
+	return nil
+}
+
+// createRegionalCloudRunBackendServices creates the Cloud Run NEGs and the regional
+// backend services routing to them.
+func (f *FullStack) createRegionalCloudRunBackendServices(ctx *pulumi.Context, serviceName string, args *NetworkArgs) (*compute.RegionBackendService, *compute.RegionBackendService, error) {
+	err := f.createCloudRunServerlessNEGs(ctx, serviceName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	description := fmt.Sprintf("regional service backend for %s", serviceName)
+	backendServiceArgs := toRegionBackendServiceArgs(f.newUpstreamServiceArgs(description, args, UpstreamBackend), f.Region)
+	frontendServiceArgs := toRegionBackendServiceArgs(f.newUpstreamServiceArgs(description, args, UpstreamFrontend), f.Region)
+
+	backendServiceName := f.NewResourceName(serviceName, "regional-cloudrun-backend-service", 63)
+	backendService, err := compute.NewRegionBackendService(ctx, backendServiceName, backendServiceArgs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create regional backend service for NEG: %w", err)
+	}
+
+	frontendServiceName := f.NewResourceName(serviceName, "regional-cloudrun-frontend-service", 63)
+	frontendService, err := compute.NewRegionBackendService(ctx, frontendServiceName, frontendServiceArgs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create regional frontend service for NEG: %w", err)
+	}
+
+	f.regionBackendLBService = backendService
+	f.regionFrontendLBService = frontendService
+
+	err = f.grantRegionIAPAccess(ctx, serviceName, args, UpstreamBackend, backendService)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = f.grantRegionIAPAccess(ctx, serviceName, args, UpstreamFrontend, frontendService)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return backendService, frontendService, nil
+}
+
+// toRegionBackendServiceArgs converts the args of a global backend service to the args of a
+// regional one. Regional backend services of Cloud Run NEGs require the HTTP protocol and a
+// balancing mode.
+func toRegionBackendServiceArgs(serviceArgs *compute.BackendServiceArgs, region string) *compute.RegionBackendServiceArgs {
+	regionArgs := &compute.RegionBackendServiceArgs{
+		Description:                  serviceArgs.Description,
+		Project:                      serviceArgs.Project,
+		Region:                       pulumi.String(region),
+		LoadBalancingScheme:          serviceArgs.LoadBalancingScheme,
+		Protocol:                     pulumi.String("HTTP"),
+		ConnectionDrainingTimeoutSec: serviceArgs.ConnectionDrainingTimeoutSec,
+	}
+
+	backends := compute.RegionBackendServiceBackendArray{}
+	if serviceBackends, ok := serviceArgs.Backends.(compute.BackendServiceBackendArray); ok {
+		for _, backend := range serviceBackends {
+			if backend, ok := backend.(*compute.BackendServiceBackendArgs); ok {
+				backends = append(backends, &compute.RegionBackendServiceBackendArgs{
+					Group:          backend.Group,
+					BalancingMode:  pulumi.String("UTILIZATION"),
+					CapacityScaler: pulumi.Float64(1),
+				})
+			}
+		}
+	}
+	regionArgs.Backends = backends
+
+	if logConfig, ok := serviceArgs.LogConfig.(*compute.BackendServiceLogConfigArgs); ok {
+		regionArgs.LogConfig = &compute.RegionBackendServiceLogConfigArgs{
+			Enable:         logConfig.Enable,
+			SampleRate:     logConfig.SampleRate,
+			OptionalMode:   logConfig.OptionalMode,
+			OptionalFields: logConfig.OptionalFields,
+		}
+	}
+
+	if iap, ok := serviceArgs.Iap.(*compute.BackendServiceIapArgs); ok {
+		regionArgs.Iap = &compute.RegionBackendServiceIapArgs{
+			Enabled:            iap.Enabled,
+			Oauth2ClientId:     iap.Oauth2ClientId,
+			Oauth2ClientSecret: iap.Oauth2ClientSecret,
+		}
+	}
+
+	return regionArgs
+}
+
+// routeTrafficToRegionalCloudRunInstances is the regional counterpart of
+// routeTrafficToCloudRunInstances and returns the regional URL map.
+func (f *FullStack) routeTrafficToRegionalCloudRunInstances(ctx *pulumi.Context, serviceName string, args *NetworkArgs) (*compute.RegionUrlMap, error) {
+	routing := args.Routing
+
+	backendService, frontendService, err := f.createRegionalCloudRunBackendServices(ctx, serviceName, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create regional Cloud Run backend services: %w", err)
+	}
+
+	upstreamServices := map[string]pulumi.StringOutput{
+		UpstreamBackend:  backendService.SelfLink,
+		UpstreamFrontend: frontendService.SelfLink,
+	}
+
+	hostRules, pathMatchers := toRegionURLMapHostRules(newURLMapHosts(args), upstreamServices)
+
+	urlMapName := f.NewResourceName(serviceName, "regional-url-map", 63)
+	urlMap, err := compute.NewRegionUrlMap(ctx, urlMapName, &compute.RegionUrlMapArgs{
+		Description:    pulumi.String(fmt.Sprintf("regional URL map to LB traffic for %s", serviceName)),
+		Project:        pulumi.String(f.Project),
+		Region:         pulumi.String(f.Region),
+		DefaultService: upstreamServices[routing.DefaultUpstream],
+		PathMatchers:   pathMatchers,
+		HostRules:      hostRules,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create regional URL map for Cloud Run: %w", err)
+	}
+	f.regionURLMap = urlMap
+
+	return urlMap, nil
+}
+
+// newRegionHTTPSProxy creates the regional target HTTPS proxy with its regional certificate
+// and SSL policy, and either the internet or the internal entrypoint with its DNS records.
+func (f *FullStack) newRegionHTTPSProxy(ctx *pulumi.Context, serviceName string, args *NetworkArgs, urlMap *compute.RegionUrlMap) error {
+	sslPolicy, err := f.newRegionSSLPolicy(ctx, serviceName, args.SSLPolicy)
+	if err != nil {
+		return err
+	}
+	f.regionSSLPolicy = sslPolicy
+
+	httpsProxyArgs := &compute.RegionTargetHttpsProxyArgs{
+		Description: pulumi.String(fmt.Sprintf("regional proxy to LB traffic for %s", serviceName)),
+		Project:     pulumi.String(f.Project),
+		Region:      pulumi.String(f.Region),
+		UrlMap:      urlMap.SelfLink,
+		SslPolicy:   sslPolicy.SelfLink,
+	}
+
+	if args.TLSCertificate != nil {
+		sslCertificate, err := f.newRegionSelfManagedCertificate(ctx, serviceName, args.TLSCertificate)
+		if err != nil {
+			return err
+		}
+		f.regionSSLCertificate = sslCertificate
+
+		httpsProxyArgs.SslCertificates = pulumi.StringArray{
+			sslCertificate.SelfLink,
+		}
+	} else {
+		// Regional proxies reference Certificate Manager certificates directly instead of a map
+		certificate, _, err := f.newCertificateManagerCertificate(ctx, serviceName, args.CertificateManager, f.Region)
+		if err != nil {
+			return fmt.Errorf("failed to create regional certificate: %w", err)
+		}
+
+		httpsProxyArgs.CertificateManagerCertificates = pulumi.StringArray{
+			certificate.ID(),
+		}
+	}
+
+	httpsProxyName := f.NewResourceName(serviceName, "regional-https-proxy", 63)
+	httpsProxy, err := compute.NewRegionTargetHttpsProxy(ctx, httpsProxyName, httpsProxyArgs)
+	if err != nil {
+		return fmt.Errorf("failed to create regional target HTTPS proxy: %w", err)
+	}
+
+	var httpProxy *compute.RegionTargetHttpProxy
+	if !args.HTTPSRedirect.Disabled {
+		httpProxy, err = f.newRegionHTTPRedirectProxy(ctx, serviceName, args.HTTPSRedirect)
+		if err != nil {
+			return fmt.Errorf("failed to create regional HTTP redirect proxy: %w", err)
+		}
+	}
+
+	if usesInternalLoadBalancer(args) {
+		lbIPAddress, err := f.createInternalEntrypoint(ctx, serviceName, args, httpsProxy, httpProxy)
+		if err != nil {
+			return fmt.Errorf("failed to create internal entrypoint: %w", err)
+		}
+
+		err = f.createPrivateDNSRecords(ctx, serviceName, args, lbIPAddress)
+		if err != nil {
+			return err
+		}
+
+		if args.InternalLoadBalancer.PrivateServiceConnect != nil {
+			return f.newServiceAttachment(ctx, serviceName, args)
+		}
+
+		return nil
+	}
+
+	lbIPAddress, err := f.createRegionalExternalManagedEntrypoint(ctx, serviceName, args, httpsProxy, httpProxy)
+	if err != nil {
+		return fmt.Errorf("failed to create regional internet entrypoint: %w", err)
+	}
+
+	return f.createLoadBalancerDNSRecords(ctx, serviceName, args, lbIPAddress, pulumi.StringOutput{})
+}
+
+// createRegionalExternalManagedEntrypoint creates a regional IP address and forwarding rules
+// in the proxy network for the regional external load balancer.
+// The HTTP forwarding rule is only created if an HTTP proxy is given.
+func (f *FullStack) createRegionalExternalManagedEntrypoint(ctx *pulumi.Context,
+	serviceName string,
+	args *NetworkArgs,
+	httpsProxy *compute.RegionTargetHttpsProxy,
+	httpProxy *compute.RegionTargetHttpProxy) (pulumi.StringOutput, error) {
+	labels := mergeLabels(f.Labels, pulumi.StringMap{
+		"load_balancer": pulumi.String("true"),
+	})
+
+	ipAddressName := f.NewResourceName(serviceName, "regional-ip", 63)
+	ipAddress, err := compute.NewAddress(ctx, ipAddressName, &compute.AddressArgs{
+		Project:     pulumi.String(f.Project),
+		Region:      pulumi.String(f.Region),
+		Description: pulumi.String(fmt.Sprintf("Regional IP address for %s", serviceName)),
+		AddressType: pulumi.String("EXTERNAL"),
+		Labels:      labels,
+	})
+	if err != nil {
+		return pulumi.StringOutput{}, fmt.Errorf("failed to reserve regional IP address: %w", err)
+	}
+
+	opts := f.proxySubnetDependency()
+
+	forwardingRuleName := f.NewResourceName(serviceName, "regional-https-forwarding", 63)
+	trafficRule, err := compute.NewForwardingRule(ctx, forwardingRuleName, &compute.ForwardingRuleArgs{
+		Description:         pulumi.String(fmt.Sprintf("HTTPS forwarding rule to LB traffic for %s", serviceName)),
+		Project:             pulumi.String(f.Project),
+		Region:              pulumi.String(f.Region),
+		PortRange:           pulumi.String("443"),
+		LoadBalancingScheme: pulumi.String(f.loadBalancingScheme),
+		Network:             pulumi.String(proxyNetwork(args)),
+		Target:              httpsProxy.SelfLink,
+		IpAddress:           ipAddress.Address,
+		Labels:              labels,
+	}, opts...)
+	if err != nil {
+		return pulumi.StringOutput{}, fmt.Errorf("failed to create regional forwarding rule: %w", err)
+	}
+	f.regionalForwardingRule = trafficRule
+
+	if httpProxy != nil {
+		// Plain HTTP traffic lands on the same IP to get redirected to HTTPS
+		httpForwardingRuleName := f.NewResourceName(serviceName, "regional-http-forwarding", 63)
+		httpTrafficRule, err := compute.NewForwardingRule(ctx, httpForwardingRuleName, &compute.ForwardingRuleArgs{
+			Description:         pulumi.String(fmt.Sprintf("HTTP forwarding rule to redirect LB traffic for %s", serviceName)),
+			Project:             pulumi.String(f.Project),
+			Region:              pulumi.String(f.Region),
+			PortRange:           pulumi.String("80"),
+			LoadBalancingScheme: pulumi.String(f.loadBalancingScheme),
+			Network:             pulumi.String(proxyNetwork(args)),
+			Target:              httpProxy.SelfLink,
+			IpAddress:           ipAddress.Address,
+			Labels:              labels,
+		}, opts...)
+		if err != nil {
+			return pulumi.StringOutput{}, fmt.Errorf("failed to create regional HTTP forwarding rule: %w", err)
+		}
+		f.regionalHTTPForwardingRule = httpTrafficRule
+	}
+
+	return ipAddress.Address, nil
+}
@@ -0,0 +1,423 @@
+package gcp
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	compute "github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/compute"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// Route rule weights are relative and capped by the URL map API
+const maxRouteRuleWeight = 1000
+
+// hasRouteRules returns true if the routing or any of its host rules has route rules.
+func hasRouteRules(args *RoutingArgs) bool {
+	if args == nil {
+		return false
+	}
+
+	if len(args.RouteRules) > 0 {
+		return true
+	}
+
+	for _, hostRule := range args.HostRules {
+		if hostRule != nil && len(hostRule.RouteRules) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validateRouteRules checks priorities are unique and each rule has valid matches
+// and exactly one kind of destination.
+func validateRouteRules(rules []*RouteRuleArgs) error {
+	seenPriorities := map[int]int{}
+
+	for index, rule := range rules {
+		if rule == nil {
+			return fmt.Errorf("route rule %d must not be empty", index)
+		}
+
+		if rule.Priority < 0 || rule.Priority > math.MaxInt32 {
+			return fmt.Errorf("route rule %d: priority must be between 0 and %d, got %d", index, math.MaxInt32, rule.Priority)
+		}
+
+		if previous, ok := seenPriorities[rule.Priority]; ok {
+			return fmt.Errorf("priority %d of route rule %d conflicts with route rule %d", rule.Priority, index, previous)
+		}
+		seenPriorities[rule.Priority] = index
+
+		if len(rule.Matches) == 0 {
+			return fmt.Errorf("route rule %d must have at least one match", index)
+		}
+
+		for _, match := range rule.Matches {
+			if err := validateRouteMatch(match); err != nil {
+				return fmt.Errorf("route rule %d: %w", index, err)
+			}
+		}
+
+		if err := validateRouteDestination(rule); err != nil {
+			return fmt.Errorf("route rule %d: %w", index, err)
+		}
+
+		if rule.PrefixRewrite != "" && !strings.HasPrefix(rule.PrefixRewrite, "/") {
+			return fmt.Errorf("route rule %d: prefix rewrite %q must start with /", index, rule.PrefixRewrite)
+		}
+
+		if rule.MirrorUpstream != "" {
+			if err := validateUpstream(rule.MirrorUpstream); err != nil {
+				return fmt.Errorf("route rule %d: invalid mirror upstream: %w", index, err)
+			}
+		}
+
+		if rule.Retry != nil && rule.Retry.NumRetries <= 0 {
+			return fmt.Errorf("route rule %d: number of retries must be greater than 0", index)
+		}
+	}
+
+	return nil
+}
+
+func validateRouteMatch(match *RouteMatchArgs) error {
+	if match == nil || (match.PathPrefix == "") == (match.FullPath == "") {
+		return fmt.Errorf("match must have either a path prefix or a full path")
+	}
+
+	for _, path := range []string{match.PathPrefix, match.FullPath} {
+		if path != "" && !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("path %q must start with /", path)
+		}
+	}
+
+	for _, header := range match.Headers {
+		if err := validateHeaderMatch(header); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateHeaderMatch(header *HeaderMatchArgs) error {
+	if header == nil || header.Name == "" {
+		return fmt.Errorf("header match must have a name")
+	}
+
+	conditions := 0
+	for _, set := range []bool{header.Exact != "", header.Prefix != "", header.Regex != "", header.Present} {
+		if set {
+			conditions++
+		}
+	}
+	if conditions != 1 {
+		return fmt.Errorf("header match %s must have exactly one of exact, prefix, regex or present", header.Name)
+	}
+
+	if header.Regex != "" {
+		if _, err := regexp.Compile(header.Regex); err != nil {
+			return fmt.Errorf("header match %s has an invalid regex: %w", header.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func validateRouteDestination(rule *RouteRuleArgs) error {
+	if (rule.Upstream == "") == (len(rule.WeightedUpstreams) == 0) {
+		return fmt.Errorf("must have either an upstream or weighted upstreams")
+	}
+
+	if rule.Upstream != "" {
+		return validateUpstream(rule.Upstream)
+	}
+
+	totalWeight := 0
+	for _, weighted := range rule.WeightedUpstreams {
+		if weighted == nil {
+			return fmt.Errorf("weighted upstream must not be empty")
+		}
+		if err := validateUpstream(weighted.Upstream); err != nil {
+			return err
+		}
+		if weighted.Weight < 0 || weighted.Weight > maxRouteRuleWeight {
+			return fmt.Errorf("weight of upstream %s must be between 0 and %d, got %d", weighted.Upstream, maxRouteRuleWeight, weighted.Weight)
+		}
+		totalWeight += weighted.Weight
+	}
+
+	if totalWeight == 0 {
+		return fmt.Errorf("weighted upstreams must have a total weight greater than 0")
+	}
+
+	return nil
+}
+
+// flattenRouteRules returns the routing of a path matcher as route rules in evaluation order,
+// followed by a catch-all rule to the default upstream. Path rules are converted in the order
+// they're declared in, which validatePathRules guarantees matches the longest path first.
+func flattenRouteRules(rules []*PathRuleArgs, routeRules []*RouteRuleArgs, defaultUpstream string) []*RouteRuleArgs {
+	flattened := make([]*RouteRuleArgs, 0, len(rules)+len(routeRules)+1)
+
+	if len(routeRules) > 0 {
+		flattened = append(flattened, routeRules...)
+		slices.SortStableFunc(flattened, func(a, b *RouteRuleArgs) int {
+			return a.Priority - b.Priority
+		})
+	}
+
+	for _, rule := range rules {
+		matches := make([]*RouteMatchArgs, 0, len(rule.Paths))
+		for _, path := range rule.Paths {
+			if prefix, ok := strings.CutSuffix(path, "*"); ok {
+				matches = append(matches, &RouteMatchArgs{PathPrefix: prefix})
+			} else {
+				matches = append(matches, &RouteMatchArgs{FullPath: path})
+			}
+		}
+
+		flattened = append(flattened, &RouteRuleArgs{
+			Matches:       matches,
+			Upstream:      rule.Upstream,
+			PrefixRewrite: rule.PrefixRewrite,
+		})
+	}
+
+	return append(flattened, &RouteRuleArgs{
+		Matches:  []*RouteMatchArgs{{PathPrefix: "/"}},
+		Upstream: defaultUpstream,
+	})
+}
+
+// withHeaderMatch returns copies of the matches also matching the header.
+func withHeaderMatch(matches []*RouteMatchArgs, header *HeaderMatchArgs) []*RouteMatchArgs {
+	headerMatches := make([]*RouteMatchArgs, 0, len(matches))
+	for _, match := range matches {
+		headerMatch := *match
+		headerMatch.Headers = append(slices.Clone(match.Headers), header)
+		headerMatches = append(headerMatches, &headerMatch)
+	}
+
+	return headerMatches
+}
+
+// newRouteRules creates the URL map route rules of a path matcher.
+//
+// See:
+// https://cloud.google.com/load-balancing/docs/https/traffic-management-global
+func newRouteRules(rules []*RouteRuleArgs, upstreamServices map[string]pulumi.StringOutput) compute.URLMapPathMatcherRouteRuleArray {
+	routeRules := compute.URLMapPathMatcherRouteRuleArray{}
+
+	for _, rule := range rules {
+		matchRules := compute.URLMapPathMatcherRouteRuleMatchRuleArray{}
+		for _, match := range rule.Matches {
+			matchRule := &compute.URLMapPathMatcherRouteRuleMatchRuleArgs{}
+			if match.PathPrefix != "" {
+				matchRule.PrefixMatch = pulumi.String(match.PathPrefix)
+			} else {
+				matchRule.FullPathMatch = pulumi.String(match.FullPath)
+			}
+
+			headerMatches := compute.URLMapPathMatcherRouteRuleMatchRuleHeaderMatchArray{}
+			for _, header := range match.Headers {
+				headerMatch := &compute.URLMapPathMatcherRouteRuleMatchRuleHeaderMatchArgs{
+					HeaderName:  pulumi.String(header.Name),
+					InvertMatch: pulumi.Bool(header.Invert),
+				}
+				switch {
+				case header.Exact != "":
+					headerMatch.ExactMatch = pulumi.String(header.Exact)
+				case header.Prefix != "":
+					headerMatch.PrefixMatch = pulumi.String(header.Prefix)
+				case header.Regex != "":
+					headerMatch.RegexMatch = pulumi.String(header.Regex)
+				default:
+					headerMatch.PresentMatch = pulumi.Bool(true)
+				}
+				headerMatches = append(headerMatches, headerMatch)
+			}
+			if len(headerMatches) > 0 {
+				matchRule.HeaderMatches = headerMatches
+			}
+
+			matchRules = append(matchRules, matchRule)
+		}
+
+		routeRule := &compute.URLMapPathMatcherRouteRuleArgs{
+			Priority:   pulumi.Int(rule.Priority),
+			MatchRules: matchRules,
+		}
+
+		routeAction := &compute.URLMapPathMatcherRouteRuleRouteActionArgs{}
+		hasRouteAction := false
+
+		if rule.Upstream != "" {
+			routeRule.Service = upstreamServices[rule.Upstream]
+		} else {
+			weightedServices := compute.URLMapPathMatcherRouteRuleRouteActionWeightedBackendServiceArray{}
+			for _, weighted := range rule.WeightedUpstreams {
+				weightedServices = append(weightedServices, &compute.URLMapPathMatcherRouteRuleRouteActionWeightedBackendServiceArgs{
+					BackendService: upstreamServices[weighted.Upstream],
+					Weight:         pulumi.Int(weighted.Weight),
+				})
+			}
+			routeAction.WeightedBackendServices = weightedServices
+			hasRouteAction = true
+		}
+
+		if rule.PrefixRewrite != "" {
+			routeAction.UrlRewrite = &compute.URLMapPathMatcherRouteRuleRouteActionUrlRewriteArgs{
+				PathPrefixRewrite: pulumi.String(rule.PrefixRewrite),
+			}
+			hasRouteAction = true
+		}
+
+		if rule.MirrorUpstream != "" {
+			routeAction.RequestMirrorPolicy = &compute.URLMapPathMatcherRouteRuleRouteActionRequestMirrorPolicyArgs{
+				BackendService: upstreamServices[rule.MirrorUpstream],
+			}
+			hasRouteAction = true
+		}
+
+		if rule.Retry != nil {
+			conditions := rule.Retry.Conditions
+			if len(conditions) == 0 {
+				conditions = []string{"5xx"}
+			}
+
+			retryPolicy := &compute.URLMapPathMatcherRouteRuleRouteActionRetryPolicyArgs{
+				NumRetries:      pulumi.Int(rule.Retry.NumRetries),
+				RetryConditions: toStringArray(conditions),
+			}
+			if rule.Retry.PerTryTimeoutSeconds > 0 {
+				retryPolicy.PerTryTimeout = &compute.URLMapPathMatcherRouteRuleRouteActionRetryPolicyPerTryTimeoutArgs{
+					Seconds: pulumi.String(strconv.Itoa(rule.Retry.PerTryTimeoutSeconds)),
+				}
+			}
+			routeAction.RetryPolicy = retryPolicy
+			hasRouteAction = true
+		}
+
+		if hasRouteAction {
+			routeRule.RouteAction = routeAction
+		}
+
+		routeRules = append(routeRules, routeRule)
+	}
+
+	return routeRules
+}
+
+// newRegionRouteRules is the regional counterpart of newRouteRules and creates the route rules
+// of a regional URL map path matcher.
+//
+// See:
+// https://cloud.google.com/load-balancing/docs/https/traffic-management-regional
+func newRegionRouteRules(rules []*RouteRuleArgs, upstreamServices map[string]pulumi.StringOutput) compute.RegionUrlMapPathMatcherRouteRuleArray {
+	routeRules := compute.RegionUrlMapPathMatcherRouteRuleArray{}
+
+	for _, rule := range rules {
+		matchRules := compute.RegionUrlMapPathMatcherRouteRuleMatchRuleArray{}
+		for _, match := range rule.Matches {
+			matchRule := &compute.RegionUrlMapPathMatcherRouteRuleMatchRuleArgs{}
+			if match.PathPrefix != "" {
+				matchRule.PrefixMatch = pulumi.String(match.PathPrefix)
+			} else {
+				matchRule.FullPathMatch = pulumi.String(match.FullPath)
+			}
+
+			headerMatches := compute.RegionUrlMapPathMatcherRouteRuleMatchRuleHeaderMatchArray{}
+			for _, header := range match.Headers {
+				headerMatch := &compute.RegionUrlMapPathMatcherRouteRuleMatchRuleHeaderMatchArgs{
+					HeaderName:  pulumi.String(header.Name),
+					InvertMatch: pulumi.Bool(header.Invert),
+				}
+				switch {
+				case header.Exact != "":
+					headerMatch.ExactMatch = pulumi.String(header.Exact)
+				case header.Prefix != "":
+					headerMatch.PrefixMatch = pulumi.String(header.Prefix)
+				case header.Regex != "":
+					headerMatch.RegexMatch = pulumi.String(header.Regex)
+				default:
+					headerMatch.PresentMatch = pulumi.Bool(true)
+				}
+				headerMatches = append(headerMatches, headerMatch)
+			}
+			if len(headerMatches) > 0 {
+				matchRule.HeaderMatches = headerMatches
+			}
+
+			matchRules = append(matchRules, matchRule)
+		}
+
+		routeRule := &compute.RegionUrlMapPathMatcherRouteRuleArgs{
+			Priority:   pulumi.Int(rule.Priority),
+			MatchRules: matchRules,
+		}
+
+		routeAction := &compute.RegionUrlMapPathMatcherRouteRuleRouteActionArgs{}
+		hasRouteAction := false
+
+		if rule.Upstream != "" {
+			routeRule.Service = upstreamServices[rule.Upstream]
+		} else {
+			weightedServices := compute.RegionUrlMapPathMatcherRouteRuleRouteActionWeightedBackendServiceArray{}
+			for _, weighted := range rule.WeightedUpstreams {
+				weightedServices = append(weightedServices, &compute.RegionUrlMapPathMatcherRouteRuleRouteActionWeightedBackendServiceArgs{
+					BackendService: upstreamServices[weighted.Upstream],
+					Weight:         pulumi.Int(weighted.Weight),
+				})
+			}
+			routeAction.WeightedBackendServices = weightedServices
+			hasRouteAction = true
+		}
+
+		if rule.PrefixRewrite != "" {
+			routeAction.UrlRewrite = &compute.RegionUrlMapPathMatcherRouteRuleRouteActionUrlRewriteArgs{
+				PathPrefixRewrite: pulumi.String(rule.PrefixRewrite),
+			}
+			hasRouteAction = true
+		}
+
+		if rule.MirrorUpstream != "" {
+			routeAction.RequestMirrorPolicy = &compute.RegionUrlMapPathMatcherRouteRuleRouteActionRequestMirrorPolicyArgs{
+				BackendService: upstreamServices[rule.MirrorUpstream],
+			}
+			hasRouteAction = true
+		}
+
+		if rule.Retry != nil {
+			conditions := rule.Retry.Conditions
+			if len(conditions) == 0 {
+				conditions = []string{"5xx"}
+			}
+
+			retryPolicy := &compute.RegionUrlMapPathMatcherRouteRuleRouteActionRetryPolicyArgs{
+				NumRetries:      pulumi.Int(rule.Retry.NumRetries),
+				RetryConditions: toStringArray(conditions),
+			}
+			if rule.Retry.PerTryTimeoutSeconds > 0 {
+				retryPolicy.PerTryTimeout = &compute.RegionUrlMapPathMatcherRouteRuleRouteActionRetryPolicyPerTryTimeoutArgs{
+					Seconds: pulumi.String(strconv.Itoa(rule.Retry.PerTryTimeoutSeconds)),
+				}
+			}
+			routeAction.RetryPolicy = retryPolicy
+			hasRouteAction = true
+		}
+
+		if hasRouteAction {
+			routeRule.RouteAction = routeAction
+		}
+
+		routeRules = append(routeRules, routeRule)
+	}
+
+	return routeRules
+}
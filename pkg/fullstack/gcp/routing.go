@@ -0,0 +1,279 @@
+package gcp
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	compute "github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/compute"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// Upstreams the load balancer can route traffic to
+const (
+	UpstreamBackend  = "backend"
+	UpstreamFrontend = "frontend"
+)
+
+// newDefaultPathRules returns the path rules used when no routing is configured.
+func newDefaultPathRules() []*PathRuleArgs {
+	return []*PathRuleArgs{
+		{
+			Paths:    []string{"/api/*"},
+			Upstream: UpstreamBackend,
+		},
+		{
+			Paths:    []string{"/*"},
+			Upstream: UpstreamFrontend,
+		},
+	}
+}
+
+// applyRoutingDefaults returns a copy of the routing config with default path rules and
+// upstream set. The caller's config is left untouched, so it can be reused across stacks.
+func applyRoutingDefaults(args *RoutingArgs) *RoutingArgs {
+	if args == nil {
+		args = &RoutingArgs{}
+	}
+
+	routing := &RoutingArgs{
+		PathRules:       slices.Clone(args.PathRules),
+		DefaultUpstream: args.DefaultUpstream,
+		HostRules:       make([]*HostRuleArgs, 0, len(args.HostRules)),
+		RouteRules:      slices.Clone(args.RouteRules),
+	}
+
+	if len(routing.PathRules) == 0 && len(routing.RouteRules) == 0 {
+		routing.PathRules = newDefaultPathRules()
+	}
+
+	if routing.DefaultUpstream == "" {
+		routing.DefaultUpstream = UpstreamBackend
+	}
+
+	for _, hostRule := range args.HostRules {
+		if hostRule == nil {
+			// Rejected by validateRouting
+			routing.HostRules = append(routing.HostRules, nil)
+
+			continue
+		}
+
+		hostRuleCopy := &HostRuleArgs{
+			Hosts:           slices.Clone(hostRule.Hosts),
+			PathRules:       slices.Clone(hostRule.PathRules),
+			DefaultUpstream: hostRule.DefaultUpstream,
+			RouteRules:      slices.Clone(hostRule.RouteRules),
+		}
+		if len(hostRuleCopy.PathRules) == 0 && len(hostRuleCopy.RouteRules) == 0 {
+			hostRuleCopy.PathRules = slices.Clone(routing.PathRules)
+			hostRuleCopy.RouteRules = slices.Clone(routing.RouteRules)
+		}
+		if hostRuleCopy.DefaultUpstream == "" {
+			hostRuleCopy.DefaultUpstream = routing.DefaultUpstream
+		}
+		routing.HostRules = append(routing.HostRules, hostRuleCopy)
+	}
+
+	return routing
+}
+
+// validateRouting rejects routing configs the URL map can't honor as declared.
+func validateRouting(domains []string, args *RoutingArgs) error {
+	if err := validateUpstream(args.DefaultUpstream); err != nil {
+		return fmt.Errorf("invalid default upstream: %w", err)
+	}
+
+	if len(args.PathRules) > 0 && len(args.RouteRules) > 0 {
+		return fmt.Errorf("path rules and route rules are mutually exclusive")
+	}
+
+	if err := validatePathRules(args.PathRules); err != nil {
+		return fmt.Errorf("invalid path rules for domains %v: %w", domains, err)
+	}
+
+	if err := validateRouteRules(args.RouteRules); err != nil {
+		return fmt.Errorf("invalid route rules for domains %v: %w", domains, err)
+	}
+
+	seenHosts := map[string]bool{}
+	for _, domain := range domains {
+		seenHosts[domain] = true
+	}
+	for index, hostRule := range args.HostRules {
+		if hostRule == nil || len(hostRule.Hosts) == 0 {
+			return fmt.Errorf("host rule %d must have at least one host", index)
+		}
+
+		for _, host := range hostRule.Hosts {
+			if host == "" || host == "*" {
+				return fmt.Errorf("host rule %d has invalid host %q", index, host)
+			}
+			if seenHosts[host] {
+				return fmt.Errorf("host %s is declared by more than one host rule", host)
+			}
+			seenHosts[host] = true
+		}
+
+		if err := validateUpstream(hostRule.DefaultUpstream); err != nil {
+			return fmt.Errorf("invalid default upstream for host rule %d: %w", index, err)
+		}
+
+		if len(hostRule.PathRules) > 0 && len(hostRule.RouteRules) > 0 {
+			return fmt.Errorf("path rules and route rules of host rule %d are mutually exclusive", index)
+		}
+
+		if err := validatePathRules(hostRule.PathRules); err != nil {
+			return fmt.Errorf("invalid path rules for host rule %d: %w", index, err)
+		}
+
+		if err := validateRouteRules(hostRule.RouteRules); err != nil {
+			return fmt.Errorf("invalid route rules for host rule %d: %w", index, err)
+		}
+	}
+
+	return nil
+}
+
+func validateUpstream(upstream string) error {
+	if upstream != UpstreamBackend && upstream != UpstreamFrontend {
+		return fmt.Errorf("upstream must be %q or %q, got %q", UpstreamBackend, UpstreamFrontend, upstream)
+	}
+
+	return nil
+}
+
+// validatePathRules checks paths are well-formed, declared once and not shadowed by
+// the wildcard of a preceding rule. The load balancer matches the longest path first,
+// so a shadowed rule would silently take precedence over the order it was declared in.
+func validatePathRules(rules []*PathRuleArgs) error {
+	seenPaths := map[string]int{}
+	var precedingWildcards []string
+
+	for index, rule := range rules {
+		if rule == nil || len(rule.Paths) == 0 {
+			return fmt.Errorf("path rule %d must have at least one path", index)
+		}
+
+		if err := validateUpstream(rule.Upstream); err != nil {
+			return fmt.Errorf("path rule %d: %w", index, err)
+		}
+
+		if rule.PrefixRewrite != "" && !strings.HasPrefix(rule.PrefixRewrite, "/") {
+			return fmt.Errorf("path rule %d: prefix rewrite %q must start with /", index, rule.PrefixRewrite)
+		}
+
+		for _, path := range rule.Paths {
+			if err := validatePath(path); err != nil {
+				return fmt.Errorf("path rule %d: %w", index, err)
+			}
+
+			if previous, ok := seenPaths[path]; ok {
+				return fmt.Errorf("path %s of rule %d conflicts with rule %d", path, index, previous)
+			}
+			seenPaths[path] = index
+
+			for _, wildcard := range precedingWildcards {
+				if strings.HasPrefix(path, strings.TrimSuffix(wildcard, "*")) {
+					return fmt.Errorf("path %s of rule %d is shadowed by preceding path %s", path, index, wildcard)
+				}
+			}
+		}
+
+		for _, path := range rule.Paths {
+			if strings.HasSuffix(path, "*") {
+				precedingWildcards = append(precedingWildcards, path)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validatePath(path string) error {
+	if !strings.HasPrefix(path, "/") {
+		return fmt.Errorf("path %q must start with /", path)
+	}
+
+	wildcardIndex := strings.Index(path, "*")
+	if wildcardIndex >= 0 && (wildcardIndex != len(path)-1 || !strings.HasSuffix(path, "/*")) {
+		return fmt.Errorf("path %q can only have a wildcard at the end after a /", path)
+	}
+
+	return nil
+}
+
+// newPathMatcher creates a URL map path matcher from either the ordered path rules
+// or the route rules.
+func newPathMatcher(name string,
+	rules []*PathRuleArgs,
+	routeRules []*RouteRuleArgs,
+	defaultUpstream string,
+	upstreamServices map[string]pulumi.StringOutput) *compute.URLMapPathMatcherArgs {
+	if len(routeRules) > 0 {
+		return &compute.URLMapPathMatcherArgs{
+			Name:           pulumi.String(name),
+			DefaultService: upstreamServices[defaultUpstream],
+			RouteRules:     newRouteRules(routeRules, upstreamServices),
+		}
+	}
+
+	return &compute.URLMapPathMatcherArgs{
+		Name:           pulumi.String(name),
+		DefaultService: upstreamServices[defaultUpstream],
+		PathRules:      newPathRules(rules, upstreamServices),
+	}
+}
+
+// newPathRules creates the URL map path rules routing the paths to their upstream.
+func newPathRules(rules []*PathRuleArgs, upstreamServices map[string]pulumi.StringOutput) compute.URLMapPathMatcherPathRuleArray {
+	pathRules := compute.URLMapPathMatcherPathRuleArray{}
+	for _, rule := range rules {
+		pathRule := &compute.URLMapPathMatcherPathRuleArgs{
+			Paths:   toStringArray(rule.Paths),
+			Service: upstreamServices[rule.Upstream],
+		}
+
+		if rule.PrefixRewrite != "" {
+			pathRule.RouteAction = &compute.URLMapPathMatcherPathRuleRouteActionArgs{
+				UrlRewrite: &compute.URLMapPathMatcherPathRuleRouteActionUrlRewriteArgs{
+					PathPrefixRewrite: pulumi.String(rule.PrefixRewrite),
+				},
+			}
+		}
+
+		pathRules = append(pathRules, pathRule)
+	}
+
+	return pathRules
+}
+
+// toRegionPathRules converts the path rules to the path rules of a regional URL map.
+func toRegionPathRules(pathRules compute.URLMapPathMatcherPathRuleArray) compute.RegionUrlMapPathMatcherPathRuleArray {
+	regionPathRules := compute.RegionUrlMapPathMatcherPathRuleArray{}
+	for _, rule := range pathRules {
+		pathRule, ok := rule.(*compute.URLMapPathMatcherPathRuleArgs)
+		if !ok {
+			continue
+		}
+
+		regionPathRule := &compute.RegionUrlMapPathMatcherPathRuleArgs{
+			Paths:   pathRule.Paths,
+			Service: pathRule.Service,
+		}
+
+		if routeAction, ok := pathRule.RouteAction.(*compute.URLMapPathMatcherPathRuleRouteActionArgs); ok {
+			if urlRewrite, ok := routeAction.UrlRewrite.(*compute.URLMapPathMatcherPathRuleRouteActionUrlRewriteArgs); ok {
+				regionPathRule.RouteAction = &compute.RegionUrlMapPathMatcherPathRuleRouteActionArgs{
+					UrlRewrite: &compute.RegionUrlMapPathMatcherPathRuleRouteActionUrlRewriteArgs{
+						PathPrefixRewrite: urlRewrite.PathPrefixRewrite,
+					},
+				}
+			}
+		}
+
+		regionPathRules = append(regionPathRules, regionPathRule)
+	}
+
+	return regionPathRules
+}
@@ -2,11 +2,43 @@ package gcp
 
 import (
 	"fmt"
+	"net"
+	"regexp"
+	"strings"
 
 	compute "github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/compute"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 )
 
+const (
+	// Priority of the rule every security policy must have, matching all requests
+	defaultRulePriority = 2147483647
+	// Priorities of the generated rules, from the highest
+	ipAllowlistRulesBasePriority   = 1
+	preconfiguredRulesBasePriority = 20
+
+	// Client IP ranges and named IP lists matched by the allowlist rule, within the subexpressions
+	// limit of Cloud Armor
+	maxIPAllowlistEntries = 5
+)
+
+// Cloud Armor named IP lists of third-party providers, e.g. "sourceiplist-cloudflare"
+var namedIPListPattern = regexp.MustCompile("^sourceiplist-[a-z0-9-]+$")
+
+// Preconfigured WAF rules, evaluated in order from preconfiguredRulesBasePriority
+var preconfiguredWAFRules = []string{
+	"sqli-v33-stable",
+	"xss-v33-stable",
+	"lfi-v33-stable",
+	"rfi-v33-stable",
+	"rce-v33-stable",
+	"methodenforcement-v33-stable",
+	"scannerdetection-v33-stable",
+	"protocolattack-v33-stable",
+	"sessionfixation-v33-stable",
+	"nodejs-v33-stable",
+}
+
 // creates a best-practice Cloud Armor security policy.
 // See:
 // https://github.com/GoogleCloudPlatform/terraform-google-cloud-armor/blob/9ea03ee3ff0778a087888582e806da7342635d69/main.tf#L445
@@ -22,26 +54,61 @@ func (f *FullStack) newCloudArmorPolicy(ctx *pulumi.Context, policyName string,
 	rules = append(rules, defaultRules...)
 	rules = append(rules, preconfiguredRules...)
 
-	if len(args.ClientIPAllowlist) > 0 {
+	if args.GeoAccess != nil {
+		rules = append(rules, newGeoAccessRule(args.GeoAccess))
+	}
+
+	if isInMaintenance(args) && len(args.MaintenanceMode.ClientIPAllowlist) > 0 {
+		pageURL := fmt.Sprintf("https://storage.googleapis.com/%s/%s", f.maintenanceBucketName(policyName), maintenancePageObject)
+		rules = append(rules, newMaintenanceRule(args.MaintenanceMode, pageURL))
+	}
+
+	if len(args.ClientIPAllowlist) > 0 || len(args.ClientIPAllowlistNamedLists) > 0 {
 		// IP allowlist rule to restrict access to a handful of IPs... not for the enterprise
-		ipAllowlistRules := newIPAllowlistRules(args.ClientIPAllowlist)
-		rules = append(rules, ipAllowlistRules...)
+		rules = append(rules, newIPAllowlistRule(args.ClientIPAllowlist, args.ClientIPAllowlistNamedLists))
 	}
 
-	// TODO allow reCAPTCHA
-	// TODO add rate limiting rules
-	// TODO add named IP preconfigured rules
+	if args.AdaptiveProtection != nil && args.AdaptiveProtection.AutoDeploy != nil {
+		rules = append(rules, newAutoDeployRule(args.AdaptiveProtection.AutoDeploy))
+	}
 
-	cloudArmorPolicyName := f.NewResourceName(policyName, "cloudarmor", 63)
-	policy, err := compute.NewSecurityPolicy(ctx, cloudArmorPolicyName, &compute.SecurityPolicyArgs{
+	if args.BotManagement != nil {
+		botManagementRules := newBotManagementRules(args.BotManagement)
+		rules = append(rules, botManagementRules...)
+	}
+
+	if len(args.RateLimits) > 0 {
+		rateLimitRules := newRateLimitRules(args.RateLimits)
+		rules = append(rules, rateLimitRules...)
+	}
+
+	if len(args.CloudArmorRules) > 0 {
+		customRules := newCustomRules(args.CloudArmorRules)
+		rules = append(rules, customRules...)
+	}
+
+	policyArgs := &compute.SecurityPolicyArgs{
 		Description: pulumi.String(fmt.Sprintf("Cloud Armor security policy for %s", policyName)),
 		Project:     pulumi.String(f.Project),
 		Rules:       rules,
 		Type:        pulumi.String("CLOUD_ARMOR"),
-	})
+	}
+	if args.AdaptiveProtection != nil {
+		policyArgs.AdaptiveProtectionConfig = newAdaptiveProtectionConfig(args.AdaptiveProtection)
+	}
+	if f.recaptchaChallengePageKey != nil {
+		// Challenge the clients with the page of our key instead of the Google-managed one
+		policyArgs.RecaptchaOptionsConfig = &compute.SecurityPolicyRecaptchaOptionsConfigArgs{
+			RedirectSiteKey: f.recaptchaChallengePageKey.Name,
+		}
+	}
+
+	cloudArmorPolicyName := f.NewResourceName(policyName, "cloudarmor", 63)
+	policy, err := compute.NewSecurityPolicy(ctx, cloudArmorPolicyName, policyArgs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Cloud Armor policy: %w", err)
 	}
+	f.cloudArmorPolicy = policy
 
 	return policy, nil
 }
@@ -51,7 +118,7 @@ func newDefaultRule() compute.SecurityPolicyRuleTypeArray {
 	defaultRules = append(defaultRules, &compute.SecurityPolicyRuleTypeArgs{
 		Action:      pulumi.String("allow"),
 		Description: pulumi.String("Default allow rule"),
-		Priority:    pulumi.Int(2147483647),
+		Priority:    pulumi.Int(defaultRulePriority),
 		Match: &compute.SecurityPolicyRuleMatchArgs{
 			VersionedExpr: pulumi.String("SRC_IPS_V1"),
 			Config: &compute.SecurityPolicyRuleMatchConfigArgs{
@@ -65,61 +132,56 @@ func newDefaultRule() compute.SecurityPolicyRuleTypeArray {
 	return defaultRules
 }
 
-func newIPAllowlistRules(clientIPAllowlist []string) compute.SecurityPolicyRuleTypeArray {
-	ipRanges := pulumi.StringArray{}
-	for _, ip := range clientIPAllowlist {
-		ipRanges = append(ipRanges, pulumi.String(ip))
+// newIPAllowlistRule denies the clients outside of the allowlisted IP ranges and named IP lists,
+// e.g. the edge of a CDN. Unlike an allow rule, it lets the allowlisted requests through to the
+// rules that follow, so the WAF, bot management and rate limit rules still apply to them.
+//
+// See:
+// https://cloud.google.com/armor/docs/armor-named-ip
+func newIPAllowlistRule(clientIPAllowlist []string, namedLists []string) *compute.SecurityPolicyRuleTypeArgs {
+	allowlistMatches := make([]string, 0, len(clientIPAllowlist)+len(namedLists))
+	for _, ipRange := range clientIPAllowlist {
+		allowlistMatches = append(allowlistMatches, ipRangeExpression(ipRange))
+	}
+	for _, namedList := range namedLists {
+		allowlistMatches = append(allowlistMatches, fmt.Sprintf("evaluatePreconfiguredExpr('%s')", namedList))
 	}
 
-	var ipAllowlistRules compute.SecurityPolicyRuleTypeArray
-	ipAllowlistRules = append(ipAllowlistRules,
-		&compute.SecurityPolicyRuleTypeArgs{
-			Action:      pulumi.String("allow"),
-			Priority:    pulumi.Int(1),
-			Description: pulumi.String("IPs allowlist rule"),
-			Match: &compute.SecurityPolicyRuleMatchArgs{
-				VersionedExpr: pulumi.String("SRC_IPS_V1"),
-				Config: &compute.SecurityPolicyRuleMatchConfigArgs{
-					SrcIpRanges: ipRanges,
-				},
-			},
-		}, &compute.SecurityPolicyRuleTypeArgs{
-			Action:      pulumi.String("deny(403)"),
-			Description: pulumi.String("Default IP fallback deny rule"),
-			Priority:    pulumi.Int(2),
-			Match: &compute.SecurityPolicyRuleMatchArgs{
-				VersionedExpr: pulumi.String("SRC_IPS_V1"),
-				Config: &compute.SecurityPolicyRuleMatchConfigArgs{
-					SrcIpRanges: pulumi.StringArray{
-						pulumi.String("*"),
-					},
-				},
+	return &compute.SecurityPolicyRuleTypeArgs{
+		Action:      pulumi.String("deny(403)"),
+		Description: pulumi.String("Deny clients outside of the IP allowlist"),
+		Priority:    pulumi.Int(ipAllowlistRulesBasePriority),
+		Match: &compute.SecurityPolicyRuleMatchArgs{
+			Expr: &compute.SecurityPolicyRuleMatchExprArgs{
+				Expression: pulumi.String(fmt.Sprintf("!(%s)", strings.Join(allowlistMatches, " || "))),
 			},
-		})
+		},
+	}
+}
+
+// ipRangeExpression returns the CEL expression matching the clients of an IP address or a CIDR
+// range. Single addresses are matched as a range of their own.
+func ipRangeExpression(ipRange string) string {
+	if !strings.Contains(ipRange, "/") {
+		prefixLength := 32
+		if net.ParseIP(ipRange).To4() == nil {
+			prefixLength = 128
+		}
+		ipRange = fmt.Sprintf("%s/%d", ipRange, prefixLength)
+	}
 
-	return ipAllowlistRules
+	return fmt.Sprintf("inIpRange(origin.ip, '%s')", ipRange)
 }
 
 // newPreconfiguredRules returns a list of best-practice rules to deny traffic
 func newPreconfiguredRules() compute.SecurityPolicyRuleTypeArray {
 	var preconfiguredRules compute.SecurityPolicyRuleTypeArray
-	for index, rule := range []string{
-		"sqli-v33-stable",
-		"xss-v33-stable",
-		"lfi-v33-stable",
-		"rfi-v33-stable",
-		"rce-v33-stable",
-		"methodenforcement-v33-stable",
-		"scannerdetection-v33-stable",
-		"protocolattack-v33-stable",
-		"sessionfixation-v33-stable",
-		"nodejs-v33-stable",
-	} {
+	for index, rule := range preconfiguredWAFRules {
 		preconfiguredWafRule := fmt.Sprintf("evaluatePreconfiguredWaf('%s', {'sensitivity': 1})", rule)
 		preconfiguredRules = append(preconfiguredRules, &compute.SecurityPolicyRuleTypeArgs{
 			Action:      pulumi.String("deny(502)"),
 			Description: pulumi.String(fmt.Sprintf("preconfigured waf rule %s", rule)),
-			Priority:    pulumi.Int(20 + index),
+			Priority:    pulumi.Int(preconfiguredRulesBasePriority + index),
 			Match: &compute.SecurityPolicyRuleMatchArgs{
 				Expr: &compute.SecurityPolicyRuleMatchExprArgs{
 					Expression: pulumi.String(preconfiguredWafRule),
@@ -130,3 +192,44 @@ func newPreconfiguredRules() compute.SecurityPolicyRuleTypeArray {
 
 	return preconfiguredRules
 }
+
+// validateCELPath rejects the paths that can't be matched as a prefix in a CEL string literal.
+func validateCELPath(path string) error {
+	if !strings.HasPrefix(path, "/") || strings.ContainsAny(path, `'"\`) {
+		return fmt.Errorf("path %q must start with / and can't contain quotes or backslashes", path)
+	}
+
+	return nil
+}
+
+// pathPrefixExpression returns the CEL expression matching the requests under a path prefix.
+func pathPrefixExpression(path string) string {
+	return fmt.Sprintf("request.path.startsWith('%s')", path)
+}
+
+func validateClientIPAllowlist(args *NetworkArgs) error {
+	if len(args.ClientIPAllowlistNamedLists) > 0 && !args.EnableCloudArmor {
+		return fmt.Errorf("named IP lists require Cloud Armor to be enabled")
+	}
+
+	if entries := len(args.ClientIPAllowlist) + len(args.ClientIPAllowlistNamedLists); entries > maxIPAllowlistEntries {
+		return fmt.Errorf("at most %d client IP ranges and named IP lists are supported, got %d", maxIPAllowlistEntries, entries)
+	}
+
+	for _, ipRange := range args.ClientIPAllowlist {
+		if net.ParseIP(ipRange) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(ipRange); err != nil {
+			return fmt.Errorf("client IP range %q must be an IP address or a CIDR range", ipRange)
+		}
+	}
+
+	for _, namedList := range args.ClientIPAllowlistNamedLists {
+		if !namedIPListPattern.MatchString(namedList) {
+			return fmt.Errorf("named IP list %q must be a Cloud Armor source IP list, e.g. \"sourceiplist-fastly\"", namedList)
+		}
+	}
+
+	return nil
+}
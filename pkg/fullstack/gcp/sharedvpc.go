@@ -0,0 +1,75 @@
+package gcp
+
+import (
+	"fmt"
+	"strings"
+
+	compute "github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/compute"
+	"github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/organizations"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// networkPath returns the path of a VPC network, qualified with its project when it's hosted
+// in another one, e.g. a Shared VPC host project. Bare names resolve to the stack project.
+func networkPath(networkProject, network string) string {
+	if networkProject == "" || strings.Contains(network, "/") {
+		return network
+	}
+
+	return fmt.Sprintf("projects/%s/global/networks/%s", networkProject, network)
+}
+
+// subnetworkPath is the subnetwork counterpart of networkPath.
+func subnetworkPath(networkProject, region, subnetwork string) string {
+	if networkProject == "" || strings.Contains(subnetwork, "/") {
+		return subnetwork
+	}
+
+	return fmt.Sprintf("projects/%s/regions/%s/subnetworks/%s", networkProject, region, subnetwork)
+}
+
+// hostProject returns the project owning the network resources: the Shared VPC host
+// project if given, or the stack project.
+func (f *FullStack) hostProject(networkProject string) string {
+	if networkProject == "" {
+		return f.Project
+	}
+
+	return networkProject
+}
+
+// grantConnectorSubnetUser grants "roles/compute.networkUser" on the Shared VPC subnet of the
+// VPC connector to the service agents of the stack project that attach to it: the Serverless VPC
+// Access and Google APIs agents creating the connector, and the Cloud Run agent routing the egress.
+//
+// See:
+// https://cloud.google.com/run/docs/configuring/shared-vpc-service-projects#grant-permissions
+func (f *FullStack) grantConnectorSubnetUser(ctx *pulumi.Context, args *CacheInstanceArgs) ([]pulumi.Resource, error) {
+	project := organizations.LookupProjectOutput(ctx, organizations.LookupProjectOutputArgs{
+		ProjectId: pulumi.String(f.Project),
+	})
+
+	serviceAgents := map[string]pulumi.StringOutput{
+		"vpcaccess-agent": pulumi.Sprintf("serviceAccount:service-%s@gcp-sa-vpcaccess.iam.gserviceaccount.com", project.Number()),
+		"cloudservices":   pulumi.Sprintf("serviceAccount:%s@cloudservices.gserviceaccount.com", project.Number()),
+		"cloudrun-agent":  pulumi.Sprintf("serviceAccount:service-%s@serverless-robot-prod.iam.gserviceaccount.com", project.Number()),
+	}
+
+	grants := []pulumi.Resource{}
+	for _, agent := range []string{"vpcaccess-agent", "cloudservices", "cloudrun-agent"} {
+		grantName := f.NewResourceName("cache", fmt.Sprintf("%s-network-user", agent), 63)
+		grant, err := compute.NewSubnetworkIAMMember(ctx, grantName, &compute.SubnetworkIAMMemberArgs{
+			Project:    pulumi.String(args.NetworkProject),
+			Region:     pulumi.String(f.Region),
+			Subnetwork: pulumi.String(args.ConnectorSubnet),
+			Role:       pulumi.String("roles/compute.networkUser"),
+			Member:     serviceAgents[agent],
+		}, pulumi.Parent(f))
+		if err != nil {
+			return nil, fmt.Errorf("failed to grant network user to %s: %w", agent, err)
+		}
+		grants = append(grants, grant)
+	}
+
+	return grants, nil
+}
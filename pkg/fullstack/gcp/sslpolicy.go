@@ -0,0 +1,126 @@
+package gcp
+
+import (
+	"fmt"
+	"slices"
+
+	compute "github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/compute"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// SSL policy profiles
+const (
+	SSLProfileCompatible = "COMPATIBLE"
+	SSLProfileModern     = "MODERN"
+	SSLProfileRestricted = "RESTRICTED"
+	SSLProfileCustom     = "CUSTOM"
+)
+
+var (
+	sslPolicyProfiles = []string{SSLProfileCompatible, SSLProfileModern, SSLProfileRestricted, SSLProfileCustom}
+	minTLSVersions    = []string{"TLS_1_0", "TLS_1_1", "TLS_1_2"}
+	quicOverrides     = []string{"NONE", "ENABLE", "DISABLE"}
+)
+
+func applySSLPolicyDefaults(args *SSLPolicyArgs) *SSLPolicyArgs {
+	if args == nil {
+		args = &SSLPolicyArgs{}
+	}
+
+	if args.MinTLSVersion == "" {
+		args.MinTLSVersion = "TLS_1_2"
+	}
+
+	if args.Profile == "" {
+		args.Profile = SSLProfileModern
+	}
+
+	if args.QUICOverride == "" {
+		args.QUICOverride = "NONE"
+	}
+
+	return args
+}
+
+func validateSSLPolicy(args *SSLPolicyArgs) error {
+	if !slices.Contains(sslPolicyProfiles, args.Profile) {
+		return fmt.Errorf("profile must be one of %v, got %q", sslPolicyProfiles, args.Profile)
+	}
+
+	if !slices.Contains(minTLSVersions, args.MinTLSVersion) {
+		return fmt.Errorf("min TLS version must be one of %v, got %q", minTLSVersions, args.MinTLSVersion)
+	}
+
+	if !slices.Contains(quicOverrides, args.QUICOverride) {
+		return fmt.Errorf("QUIC override must be one of %v, got %q", quicOverrides, args.QUICOverride)
+	}
+
+	if args.Profile == SSLProfileCustom && len(args.CustomFeatures) == 0 {
+		return fmt.Errorf("custom features are required with the %s profile", SSLProfileCustom)
+	}
+
+	if args.Profile != SSLProfileCustom && len(args.CustomFeatures) > 0 {
+		return fmt.Errorf("custom features are only allowed with the %s profile", SSLProfileCustom)
+	}
+
+	return nil
+}
+
+// newSSLPolicy creates the SSL policy to set the TLS versions and ciphers the HTTPS
+// proxy negotiates with clients.
+//
+// See:
+// https://cloud.google.com/load-balancing/docs/ssl-policies-concepts
+func (f *FullStack) newSSLPolicy(ctx *pulumi.Context, serviceName string, args *SSLPolicyArgs) (*compute.SSLPolicy, error) {
+	policyName := f.NewResourceName(serviceName, "ssl-policy", 63)
+	policyArgs := f.newSSLPolicyArgs(policyName, fmt.Sprintf("SSL policy for %s", serviceName), args)
+
+	policy, err := compute.NewSSLPolicy(ctx, policyName, policyArgs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SSL policy: %w", err)
+	}
+
+	return policy, nil
+}
+
+// newRegionSSLPolicy creates the SSL policy of regional load balancers. Regional
+// proxies can only reference regional policies.
+func (f *FullStack) newRegionSSLPolicy(ctx *pulumi.Context, serviceName string, args *SSLPolicyArgs) (*compute.RegionSslPolicy, error) {
+	policyName := f.NewResourceName(serviceName, "regional-ssl-policy", 63)
+	policyArgs := f.newSSLPolicyArgs(policyName, fmt.Sprintf("regional SSL policy for %s", serviceName), args)
+
+	policy, err := compute.NewRegionSslPolicy(ctx, policyName, toRegionSSLPolicyArgs(policyArgs, f.Region))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create regional SSL policy: %w", err)
+	}
+
+	return policy, nil
+}
+
+func (f *FullStack) newSSLPolicyArgs(policyName, description string, args *SSLPolicyArgs) *compute.SSLPolicyArgs {
+	policyArgs := &compute.SSLPolicyArgs{
+		Name:          pulumi.String(policyName),
+		Description:   pulumi.String(description),
+		Project:       pulumi.String(f.Project),
+		MinTlsVersion: pulumi.String(args.MinTLSVersion),
+		Profile:       pulumi.String(args.Profile),
+	}
+	if args.Profile == SSLProfileCustom {
+		policyArgs.CustomFeatures = toStringArray(args.CustomFeatures)
+	}
+
+	return policyArgs
+}
+
+// toRegionSSLPolicyArgs converts the SSL policy args to the args of a regional policy.
+func toRegionSSLPolicyArgs(policyArgs *compute.SSLPolicyArgs, region string) *compute.RegionSslPolicyArgs {
+	return &compute.RegionSslPolicyArgs{
+		Name:           policyArgs.Name,
+		Description:    policyArgs.Description,
+		Project:        policyArgs.Project,
+		Region:         pulumi.String(region),
+		MinTlsVersion:  policyArgs.MinTlsVersion,
+		Profile:        policyArgs.Profile,
+		CustomFeatures: policyArgs.CustomFeatures,
+	}
+}
@@ -0,0 +1,98 @@
+package gcp
+
+import (
+	"fmt"
+
+	compute "github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/compute"
+	"github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/secretmanager"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+func validateTLSCertificate(args *TLSCertificateArgs) error {
+	fromSecrets := args.CertificateSecretID != nil || args.PrivateKeySecretID != nil
+	fromInputs := args.Certificate != nil || args.PrivateKey != nil
+
+	if fromSecrets && fromInputs {
+		return fmt.Errorf("certificate must be set either from Secret Manager or from inputs, not both")
+	}
+
+	if fromSecrets && (args.CertificateSecretID == nil || args.PrivateKeySecretID == nil) {
+		return fmt.Errorf("both the certificate and private key secret IDs are required")
+	}
+
+	if !fromSecrets && (args.Certificate == nil || args.PrivateKey == nil) {
+		return fmt.Errorf("both the certificate and private key are required")
+	}
+
+	return nil
+}
+
+// newSelfManagedCertificate creates an SSL certificate from a PEM-encoded certificate
+// chain and private key, either read from Secret Manager or given as inputs.
+//
+// The certificate is auto-named, so rotating it creates the new certificate and swaps
+// it on the proxy before the old one is deleted.
+func (f *FullStack) newSelfManagedCertificate(ctx *pulumi.Context, serviceName string, args *TLSCertificateArgs) (*compute.SSLCertificate, error) {
+	certificate, privateKey := f.tlsCertificateData(ctx, args)
+
+	tlsCertName := f.NewResourceName(serviceName, "tls-cert", 63)
+	sslCertificate, err := compute.NewSSLCertificate(ctx, tlsCertName, &compute.SSLCertificateArgs{
+		Description: pulumi.String(fmt.Sprintf("self-managed TLS cert for %s", serviceName)),
+		Project:     pulumi.String(f.Project),
+		Certificate: certificate,
+		PrivateKey:  privateKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create self-managed SSL certificate: %w", err)
+	}
+
+	return sslCertificate, nil
+}
+
+// newRegionSelfManagedCertificate creates a regional SSL certificate for regional
+// load balancers, so that TLS terminates in the load balancer region.
+func (f *FullStack) newRegionSelfManagedCertificate(ctx *pulumi.Context, serviceName string, args *TLSCertificateArgs) (*compute.RegionSslCertificate, error) {
+	certificate, privateKey := f.tlsCertificateData(ctx, args)
+
+	tlsCertName := f.NewResourceName(serviceName, "regional-tls-cert", 63)
+	sslCertificate, err := compute.NewRegionSslCertificate(ctx, tlsCertName, &compute.RegionSslCertificateArgs{
+		Description: pulumi.String(fmt.Sprintf("self-managed regional TLS cert for %s", serviceName)),
+		Project:     pulumi.String(f.Project),
+		Region:      pulumi.String(f.Region),
+		Certificate: certificate,
+		PrivateKey:  privateKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create self-managed regional SSL certificate: %w", err)
+	}
+
+	return sslCertificate, nil
+}
+
+// tlsCertificateData returns the certificate chain and private key as secrets, either
+// read from Secret Manager or given as inputs.
+func (f *FullStack) tlsCertificateData(ctx *pulumi.Context, args *TLSCertificateArgs) (pulumi.StringOutput, pulumi.StringOutput) {
+	certificate := args.Certificate
+	privateKey := args.PrivateKey
+
+	if args.CertificateSecretID != nil {
+		version := args.SecretVersion
+		if version == nil {
+			version = pulumi.String("latest")
+		}
+
+		certificate = f.lookupSecretData(ctx, args.CertificateSecretID, version)
+		privateKey = f.lookupSecretData(ctx, args.PrivateKeySecretID, version)
+	}
+
+	return pulumi.ToSecret(certificate).(pulumi.StringOutput), pulumi.ToSecret(privateKey).(pulumi.StringOutput)
+}
+
+// lookupSecretData reads the payload of a Secret Manager secret version.
+func (f *FullStack) lookupSecretData(ctx *pulumi.Context, secretID, version pulumi.StringInput) pulumi.StringOutput {
+	return secretmanager.LookupSecretVersionOutput(ctx, secretmanager.LookupSecretVersionOutputArgs{
+		Project: pulumi.String(f.Project),
+		Secret:  secretID,
+		Version: version,
+	}).SecretData()
+}
@@ -0,0 +1,133 @@
+package gcp
+
+import (
+	"fmt"
+
+	compute "github.com/pulumi/pulumi-gcp/sdk/v8/go/gcp/compute"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// urlMapHost routes a group of hosts of the URL map with a path matcher of their own. It's
+// independent of the URL map being global or regional, so both load balancers route the same
+// hosts the same way.
+type urlMapHost struct {
+	hosts       []string
+	pathMatcher string
+
+	pathRules       []*PathRuleArgs
+	routeRules      []*RouteRuleArgs
+	defaultUpstream string
+
+	// Redirects all the requests of the hosts instead of routing them to the upstreams
+	redirect *urlRedirect
+}
+
+// newURLMapHosts returns the hosts of the URL map in evaluation order: the served domains, the
+// domains redirected to the canonical one, the API domain and the additional host rules.
+func newURLMapHosts(args *NetworkArgs) []*urlMapHost {
+	routing := args.Routing
+
+	hosts := []*urlMapHost{
+		{
+			// Favor domain URLs over "*" to avoid host header attacks
+			hosts:           servedDomains(args),
+			pathMatcher:     "traffic-paths",
+			pathRules:       routing.PathRules,
+			routeRules:      routing.RouteRules,
+			defaultUpstream: routing.DefaultUpstream,
+		},
+	}
+
+	if domains := redirectedDomains(args); len(domains) > 0 {
+		hosts = append(hosts, &urlMapHost{
+			hosts:       domains,
+			pathMatcher: "canonical-redirect",
+			redirect: &urlRedirect{
+				host:         args.CanonicalDomain,
+				responseCode: redirectResponseCodes[args.HTTPSRedirect.ResponseCode],
+			},
+		})
+	}
+
+	// All the API domain traffic goes to the backend
+	if args.APIDomain != "" {
+		hosts = append(hosts, &urlMapHost{
+			hosts:           []string{args.APIDomain},
+			pathMatcher:     "api-domain-paths",
+			defaultUpstream: UpstreamBackend,
+		})
+	}
+
+	for index, hostRule := range routing.HostRules {
+		hosts = append(hosts, &urlMapHost{
+			hosts:           hostRule.Hosts,
+			pathMatcher:     fmt.Sprintf("host-paths-%d", index),
+			pathRules:       hostRule.PathRules,
+			routeRules:      hostRule.RouteRules,
+			defaultUpstream: hostRule.DefaultUpstream,
+		})
+	}
+
+	return hosts
+}
+
+// toURLMapHostRules converts the hosts to the host rules and path matchers of the global URL
+// map. The path matchers of the routed hosts are built by newPathMatcher, which lets the global
+// load balancer layer the canary, maintenance mode and error pages on top.
+func toURLMapHostRules(hosts []*urlMapHost,
+	newPathMatcher func(host *urlMapHost) *compute.URLMapPathMatcherArgs) (compute.URLMapHostRuleArray, compute.URLMapPathMatcherArray) {
+	hostRules := compute.URLMapHostRuleArray{}
+	pathMatchers := compute.URLMapPathMatcherArray{}
+
+	for _, host := range hosts {
+		pathMatcher := &compute.URLMapPathMatcherArgs{
+			Name: pulumi.String(host.pathMatcher),
+		}
+		if host.redirect != nil {
+			pathMatcher.DefaultUrlRedirect = host.redirect.toPathMatcherRedirect()
+		} else {
+			pathMatcher = newPathMatcher(host)
+		}
+
+		pathMatchers = append(pathMatchers, pathMatcher)
+		hostRules = append(hostRules, &compute.URLMapHostRuleArgs{
+			Hosts:       toStringArray(host.hosts),
+			PathMatcher: pulumi.String(host.pathMatcher),
+		})
+	}
+
+	return hostRules, pathMatchers
+}
+
+// toRegionURLMapHostRules converts the hosts to the host rules and path matchers of the
+// regional URL map. Regional load balancers don't support the canary, maintenance mode and
+// error pages, so the path matchers only route the path rules or route rules of the hosts.
+func toRegionURLMapHostRules(hosts []*urlMapHost,
+	upstreamServices map[string]pulumi.StringOutput) (compute.RegionUrlMapHostRuleArray, compute.RegionUrlMapPathMatcherArray) {
+	hostRules := compute.RegionUrlMapHostRuleArray{}
+	pathMatchers := compute.RegionUrlMapPathMatcherArray{}
+
+	for _, host := range hosts {
+		pathMatcher := &compute.RegionUrlMapPathMatcherArgs{
+			Name: pulumi.String(host.pathMatcher),
+		}
+		if host.redirect != nil {
+			pathMatcher.DefaultUrlRedirect = host.redirect.toRegionPathMatcherRedirect()
+		} else {
+			pathMatcher.DefaultService = upstreamServices[host.defaultUpstream]
+			if len(host.routeRules) > 0 {
+				pathMatcher.RouteRules = newRegionRouteRules(host.routeRules, upstreamServices)
+			} else {
+				pathMatcher.PathRules = toRegionPathRules(newPathRules(host.pathRules, upstreamServices))
+			}
+		}
+
+		pathMatchers = append(pathMatchers, pathMatcher)
+		hostRules = append(hostRules, &compute.RegionUrlMapHostRuleArgs{
+			Hosts:       toStringArray(host.hosts),
+			PathMatcher: pulumi.String(host.pathMatcher),
+		})
+	}
+
+	return hostRules, pathMatchers
+}